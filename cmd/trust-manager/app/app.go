@@ -99,7 +99,13 @@ func NewCommand() *cobra.Command {
 			}
 
 			// Register webhook handlers with manager.
-			webhook.Register(mgr, webhook.Options{Log: opts.Logr.WithName("webhook")})
+			webhook.Register(mgr, webhook.Options{
+				Log:                 opts.Logr.WithName("webhook"),
+				ExpiryWarningWindow: opts.Webhook.ExpiryWarningWindow,
+				AuthorizeSources:    opts.Webhook.AuthorizeSources,
+				PodInjectionEnabled: opts.Webhook.PodInjectionEnabled,
+				TrustNamespace:      opts.Bundle.Namespace,
+			})
 
 			// Start all runnables and controller
 			return mgr.Start(ctx)
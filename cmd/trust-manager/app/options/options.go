@@ -19,6 +19,7 @@ package options
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
@@ -66,6 +67,19 @@ type Webhook struct {
 	Host    string
 	Port    int
 	CertDir string
+
+	// ExpiryWarningWindow is the window before expiry in which certificates
+	// of inline sources are returned as warnings when a Bundle is admitted.
+	ExpiryWarningWindow time.Duration
+
+	// AuthorizeSources, if true, requires users creating or updating Bundles
+	// to be allowed to read the Secrets and ConfigMaps they reference in the
+	// trust Namespace.
+	AuthorizeSources bool
+
+	// PodInjectionEnabled, if true, serves the webhook which injects the
+	// target ConfigMap of a Bundle into annotated Pods.
+	PodInjectionEnabled bool
 }
 
 // New constructs a new Options.
@@ -152,6 +166,23 @@ func (o *Options) addBundleFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.Bundle.DefaultPackageLocation,
 		"default-package-location", "",
 		"Path to a JSON file containing the default certificate package. If set, must be a valid package.")
+
+	fs.BoolVar(&o.Bundle.SecretTargetsEnabled,
+		"secret-targets-enabled", false,
+		"If set to true, Bundles may use Secrets as targets. Requires trust-manager to have permissions to manage Secrets in all Namespaces.")
+
+	fs.BoolVar(&o.Bundle.ClusterTrustBundleTargetsEnabled,
+		"cluster-trust-bundle-targets-enabled", false,
+		"If set to true, Bundles may use ClusterTrustBundles as targets. Requires the certificates.k8s.io/v1alpha1 API to be enabled in the cluster.")
+
+	fs.BoolVar(&o.Bundle.ClusterTrustBundleSourcesEnabled,
+		"cluster-trust-bundle-sources-enabled", false,
+		"If set to true, Bundles may use ClusterTrustBundles as sources. Requires the certificates.k8s.io/v1alpha1 API to be enabled in the cluster.")
+
+	fs.DurationVar(&o.Bundle.ExpiryWarningWindow,
+		"expiry-warning-window", 0,
+		"Bundles containing certificates which expire within this window get the CertificatesExpiringSoon condition and a Warning event. "+
+			"Disabled if 0, which is the default.")
 }
 
 func (o *Options) addWebhookFlags(fs *pflag.FlagSet) {
@@ -166,4 +197,17 @@ func (o *Options) addWebhookFlags(fs *pflag.FlagSet) {
 		"Directory where the Webhook certificate and private key are located. "+
 			"Certificate and private key must be named 'tls.crt' and 'tls.key' "+
 			"respectively.")
+	fs.DurationVar(&o.Webhook.ExpiryWarningWindow,
+		"webhook-expiry-warning-window", 0,
+		"Bundles with inline source certificates which expire within this window are admitted with a warning. "+
+			"Disabled if 0, which is the default.")
+	fs.BoolVar(&o.Webhook.AuthorizeSources,
+		"webhook-authorize-sources", false,
+		"If set to true, users creating or updating Bundles must be allowed to get the Secrets and ConfigMaps "+
+			"referenced by the Bundle in the trust namespace, directly or through other Bundles. Referenced Secrets and ConfigMaps which "+
+			"don't exist are reported as warnings. Requires trust-manager to have permission to create SubjectAccessReviews.")
+	fs.BoolVar(&o.Webhook.PodInjectionEnabled,
+		"webhook-pod-injection-enabled", false,
+		"If set to true, serve the mutating webhook which injects the target ConfigMap of a Bundle into Pods "+
+			"labelled trust.cert-manager.io/inject-enabled=true and annotated with trust.cert-manager.io/inject=<bundle>.")
 }
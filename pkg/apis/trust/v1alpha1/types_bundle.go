@@ -26,6 +26,8 @@ import (
 // +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".status.target.configMap.key",description="Bundle Target Key"
 // +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=`.status.conditions[?(@.type == "Synced")].status`,description="Bundle has been synced"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=`.status.conditions[?(@.type == "Synced")].reason`,description="Reason Bundle has Synced status"
+// +kubebuilder:printcolumn:name="Certificates",type="integer",JSONPath=".status.certificates.count",description="Number of certificates in the Bundle"
+// +kubebuilder:printcolumn:name="Earliest Expiry",type="string",JSONPath=".status.certificates.earliestExpiry",description="Earliest expiry of any certificate in the Bundle"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Timestamp Bundle was created"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
@@ -58,18 +60,160 @@ type BundleSpec struct {
 
 	// Target is the target location in all namespaces to sync source data to.
 	Target BundleTarget `json:"target"`
+
+	// Filter removes certificates from the resolved source data before it is
+	// synced to the target.
+	// +optional
+	Filter *BundleFilter `json:"filter,omitempty"`
+
+	// Ordering is the order of the certificates in the target. Duplicate
+	// certificates are always removed, keeping the first occurrence.
+	// One of `Source`, `Subject` or `Fingerprint`. Defaults to `Source`,
+	// which keeps certificates in the order of the sources.
+	// +optional
+	// +kubebuilder:validation:Enum=Source;Subject;Fingerprint
+	Ordering BundleOrdering `json:"ordering,omitempty"`
+
+	// CryptoPolicy restricts the public keys and signature algorithms of the
+	// certificates in the resolved source data.
+	// +optional
+	CryptoPolicy *CryptoPolicy `json:"cryptoPolicy,omitempty"`
+
+	// CARequirement is the requirement on certificates for them to be
+	// accepted as trust anchors. One of `Any`, `CA` or `SelfSignedCA`.
+	// `CA` requires all certificates to be CA certificates with valid basic
+	// constraints, and `SelfSignedCA` additionally requires them to be
+	// self-signed root certificates. Bundles containing other certificates
+	// are not synced. Defaults to `Any`.
+	// +optional
+	// +kubebuilder:validation:Enum=Any;CA;SelfSignedCA
+	CARequirement CARequirement `json:"caRequirement,omitempty"`
+}
+
+// CARequirement is the requirement on certificates for them to be accepted as
+// trust anchors.
+type CARequirement string
+
+const (
+	// CARequirementAny accepts any certificate.
+	CARequirementAny CARequirement = "Any"
+
+	// CARequirementCA accepts CA certificates with valid basic constraints.
+	CARequirementCA CARequirement = "CA"
+
+	// CARequirementSelfSignedCA accepts self-signed root CA certificates, so
+	// that neither leaf nor intermediate certificates can be trust anchors.
+	CARequirementSelfSignedCA CARequirement = "SelfSignedCA"
+)
+
+// BundleOrdering is the order of the certificates in a Bundle target.
+type BundleOrdering string
+
+const (
+	// BundleOrderingSource keeps certificates in the order of the sources.
+	BundleOrderingSource BundleOrdering = "Source"
+
+	// BundleOrderingSubject orders certificates by their subject distinguished
+	// name, and then by their SHA-256 fingerprint.
+	BundleOrderingSubject BundleOrdering = "Subject"
+
+	// BundleOrderingFingerprint orders certificates by their SHA-256
+	// fingerprint.
+	BundleOrderingFingerprint BundleOrdering = "Fingerprint"
+)
+
+// CryptoPolicy restricts the public keys and signature algorithms of the
+// certificates in a Bundle.
+type CryptoPolicy struct {
+	// MinRSAKeySize is the minimum size in bits of RSA public keys.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinRSAKeySize *int32 `json:"minRSAKeySize,omitempty"`
+
+	// AllowedCurves, if set, is the list of curves allowed for ECDSA and EdDSA
+	// public keys. Each entry is one of `P-224`, `P-256`, `P-384`, `P-521` or
+	// `Ed25519`.
+	// +optional
+	AllowedCurves []string `json:"allowedCurves,omitempty"`
+
+	// ForbiddenSignatureAlgorithms is the list of signature algorithms which
+	// certificates must not be signed with, for example `SHA1-RSA` or
+	// `ECDSA-SHA1`.
+	// +optional
+	ForbiddenSignatureAlgorithms []string `json:"forbiddenSignatureAlgorithms,omitempty"`
+
+	// Mode is what happens to certificates which violate the policy. `Drop`
+	// removes them from the Bundle, and `Fail` stops the Bundle from being
+	// synced until they are removed from its sources. Defaults to `Drop`.
+	// +optional
+	// +kubebuilder:validation:Enum=Drop;Fail
+	Mode CryptoPolicyMode `json:"mode,omitempty"`
+}
+
+// CryptoPolicyMode is what happens to certificates which violate the crypto
+// policy of a Bundle.
+type CryptoPolicyMode string
+
+const (
+	// CryptoPolicyModeDrop removes certificates which violate the policy.
+	CryptoPolicyModeDrop CryptoPolicyMode = "Drop"
+
+	// CryptoPolicyModeFail fails the Bundle if any certificate violates the
+	// policy.
+	CryptoPolicyModeFail CryptoPolicyMode = "Fail"
+)
+
+// BundleFilter defines which certificates are removed from the resolved
+// source data of a Bundle.
+type BundleFilter struct {
+	// EnforceValidityPeriod, when true, removes certificates which are expired
+	// or not yet valid. The Bundle is synced again whenever a certificate
+	// becomes valid or expires, so that targets always contain exactly the
+	// certificates which are currently valid.
+	// +optional
+	EnforceValidityPeriod *bool `json:"enforceValidityPeriod,omitempty"`
+
+	// Include, if set, removes all certificates which match none of the
+	// given rules.
+	// +optional
+	Include []CertificateMatcher `json:"include,omitempty"`
+
+	// Exclude removes all certificates which match any of the given rules.
+	// Exclude rules are applied before include rules.
+	// +optional
+	Exclude []CertificateMatcher `json:"exclude,omitempty"`
+}
+
+// CertificateMatcher matches certificates. Exactly one field must be set.
+type CertificateMatcher struct {
+	// SHA256 matches the certificate with the given hex-encoded SHA-256
+	// fingerprint of its DER encoding.
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Subject matches certificates with the given subject distinguished name,
+	// in RFC 2253 format. For example: `CN=ISRG Root X1,O=Internet Security Research Group,C=US`.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// SubjectRegex matches certificates whose subject distinguished name, in
+	// RFC 2253 format, matches the given regular expression.
+	// +optional
+	SubjectRegex string `json:"subjectRegex,omitempty"`
 }
 
 // BundleSource is the set of sources whose data will be appended and synced to
 // the BundleTarget in all Namespaces.
 type BundleSource struct {
 	// ConfigMap is a reference to a ConfigMap's `data` key, in the trust
-	// Namespace.
+	// Namespace. Either a single ConfigMap can be referenced by name, or all
+	// ConfigMaps matching a label selector.
 	// +optional
 	ConfigMap *SourceObjectKeySelector `json:"configMap,omitempty"`
 
 	// Secret is a reference to a Secrets's `data` key, in the trust
-	// Namespace.
+	// Namespace. Either a single Secret can be referenced by name, or all
+	// Secrets matching a label selector.
 	// +optional
 	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
 
@@ -77,6 +221,28 @@ type BundleSource struct {
 	// +optional
 	InLine *string `json:"inLine,omitempty"`
 
+	// Bundle is a reference to another Bundle whose resolved source data
+	// will be appended as the source data. Bundles may not reference each
+	// other in a cycle.
+	// +optional
+	Bundle *BundleReference `json:"bundle,omitempty"`
+
+	// URL is a reference to a PEM-encoded bundle published at an HTTPS URL.
+	// The last successfully fetched data is cached in the trust Namespace, and
+	// is used as the source data if a later fetch fails.
+	// +optional
+	URL *URLSource `json:"url,omitempty"`
+
+	// ClusterTrustBundle is a reference to ClusterTrustBundles whose trust
+	// bundles will be appended as the source data. Either a single
+	// ClusterTrustBundle can be referenced by name, or all ClusterTrustBundles
+	// of a signer matching a label selector.
+	// Using ClusterTrustBundles as sources is only supported if enabled at
+	// trust-manager startup with the "--cluster-trust-bundle-sources-enabled"
+	// flag, and requires the certificates.k8s.io/v1alpha1 API to be enabled.
+	// +optional
+	ClusterTrustBundle *ClusterTrustBundleSource `json:"clusterTrustBundle,omitempty"`
+
 	// UseDefaultCAs, when true, requests the default CA bundle to be used as a source.
 	// Default CAs are available if trust-manager was installed via Helm
 	// or was otherwise set up to include a package-injecting init container by using the
@@ -94,23 +260,207 @@ type BundleSource struct {
 type BundleTarget struct {
 	// ConfigMap is the target ConfigMap in Namespaces that all Bundle source
 	// data will be synced to.
-	ConfigMap *KeySelector `json:"configMap,omitempty"`
+	// +optional
+	ConfigMap *ConfigMapTarget `json:"configMap,omitempty"`
+
+	// Secret is the target Secret in Namespaces that all Bundle source data
+	// will be synced to.
+	// Using Secrets as targets is only supported if enabled at trust-manager
+	// startup with the "--secret-targets-enabled" flag. By default,
+	// trust-manager has no permissions for writing to Secrets.
+	// +optional
+	Secret *KeySelector `json:"secret,omitempty"`
+
+	// ClusterTrustBundle is the target ClusterTrustBundle that all Bundle
+	// source data will be synced to. ClusterTrustBundles are cluster scoped
+	// and can be mounted by Pods using projected volumes, without a copy of
+	// the bundle in every Namespace.
+	// Using ClusterTrustBundles as targets is only supported if enabled at
+	// trust-manager startup with the "--cluster-trust-bundle-targets-enabled"
+	// flag, and requires the certificates.k8s.io/v1alpha1 API to be enabled.
+	// All certificates in the bundle must be CA certificates, even if the
+	// caRequirement of the Bundle is `Any`.
+	// +optional
+	ClusterTrustBundle *ClusterTrustBundleTarget `json:"clusterTrustBundle,omitempty"`
 
 	// AdditionalFormats specifies any additional formats to write to the target
 	// +optional
 	AdditionalFormats *AdditionalFormats `json:"additionalFormats,omitempty"`
 
+	// Keys is a list of additional keys which are written to the target
+	// resources, each with the format which the bundle is encoded in.
+	// Keys which are removed from the list are removed from the targets.
+	// +optional
+	Keys []TargetKey `json:"keys,omitempty"`
+
 	// NamespaceSelector will, if set, only sync the target resource in
 	// Namespaces which match the selector.
 	// +optional
 	NamespaceSelector *NamespaceSelector `json:"namespaceSelector,omitempty"`
+
+	// Metadata defines labels and annotations which are set on the target
+	// resources in every Namespace.
+	// +optional
+	Metadata *TargetMetadata `json:"metadata,omitempty"`
+}
+
+// ConfigMapTarget is the target ConfigMap and its `data` key that Bundle
+// source data will be synced to.
+type ConfigMapTarget struct {
+	// Name is the name of the target ConfigMap in every Namespace.
+	// Defaults to the name of the Bundle. When the name is changed, the
+	// target ConfigMaps with the old name are deleted.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// KeySelector is the key of the entry in the ConfigMap's `data` field
+	// that the bundle is written to.
+	KeySelector `json:",inline"`
+}
+
+// ClusterTrustBundleSource is a reference to ClusterTrustBundles whose trust
+// bundles are used as source data.
+type ClusterTrustBundleSource struct {
+	// Name is the name of a single ClusterTrustBundle.
+	// This field must be left empty when `signerName` is set.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// SignerName selects all ClusterTrustBundles which are associated with
+	// the signer. The trust bundles of all selected ClusterTrustBundles are
+	// appended to the source data, ordered by name.
+	// This field must not be set when `name` is set.
+	// +optional
+	SignerName string `json:"signerName,omitempty"`
+
+	// Selector is the label selector which the ClusterTrustBundles of the
+	// signer must match. If unset, all ClusterTrustBundles of the signer are
+	// selected. May only be set together with `signerName`.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ClusterTrustBundleTarget is the target ClusterTrustBundle that Bundle
+// source data will be synced to.
+type ClusterTrustBundleTarget struct {
+	// SignerName is the name of the signer which the ClusterTrustBundle is
+	// associated with, if any. If set, the ClusterTrustBundle is named with
+	// the signer name as a prefix, with slashes replaced by colons, followed
+	// by the name of the Bundle. Otherwise, the ClusterTrustBundle has the
+	// name of the Bundle.
+	// trust-manager must be allowed to attest for the signer.
+	// +optional
+	SignerName string `json:"signerName,omitempty"`
+}
+
+// TargetMetadata defines labels and annotations of the Bundle target
+// resources.
+type TargetMetadata struct {
+	// Labels are set on the target resources.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on the target resources. Annotations in the
+	// `trust.cert-manager.io` domain are reserved for use by trust-manager.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// TargetKey is an additional key of the target resources, and the format
+// which the bundle is written to it in.
+type TargetKey struct {
+	KeySelector `json:",inline"`
+
+	// Format is the format which the bundle is encoded in. PEM is a
+	// concatenation of PEM-encoded certificates, DER is a concatenation of
+	// DER-encoded certificates, and PKCS7 is a DER-encoded, certificates-only
+	// PKCS#7 SignedData structure. JKS and PKCS12 are binary trust stores.
+	// Defaults to PEM.
+	// +kubebuilder:validation:Enum=PEM;JKS;PKCS12;DER;PKCS7
+	// +optional
+	Format TargetFormat `json:"format,omitempty"`
+
+	// Password for JKS and PKCS12 trust stores. JKS trust stores default to
+	// the password "changeit", PKCS12 trust stores are created without a
+	// password by default.
+	// Must not be set for other formats, or when passwordSecretRef is set.
+	// +optional
+	Password *string `json:"password,omitempty"`
+
+	// PasswordSecretRef is a reference to a key in a Secret in the trust
+	// Namespace holding the password for a JKS or PKCS12 trust store.
+	// Must not be set for other formats, or when password is set.
+	// +optional
+	PasswordSecretRef *SecretKeySelector `json:"passwordSecretRef,omitempty"`
+}
+
+// TargetFormat is the format which a bundle is encoded in for a target key.
+type TargetFormat string
+
+const (
+	// TargetFormatPEM encodes the bundle as concatenated PEM certificates.
+	TargetFormatPEM TargetFormat = "PEM"
+
+	// TargetFormatJKS encodes the bundle as a JKS trust store.
+	TargetFormatJKS TargetFormat = "JKS"
+
+	// TargetFormatPKCS12 encodes the bundle as a PKCS12 trust store.
+	TargetFormatPKCS12 TargetFormat = "PKCS12"
+
+	// TargetFormatDER encodes the bundle as concatenated DER certificates.
+	TargetFormatDER TargetFormat = "DER"
+
+	// TargetFormatPKCS7 encodes the bundle as a certificates-only PKCS#7
+	// SignedData structure.
+	TargetFormatPKCS7 TargetFormat = "PKCS7"
+)
+
 // AdditionalFormats specifies any additional formats to write to the target
 type AdditionalFormats struct {
 	// JKS requests a JKS-formatted binary trust bundle to be written to the target.
-	// The bundle is created with the hardcoded password "changeit".
-	JKS *KeySelector `json:"jks,omitempty"`
+	// By default, the bundle is created with the password "changeit".
+	// +optional
+	JKS *JKS `json:"jks,omitempty"`
+
+	// PKCS12 requests a PKCS12-formatted binary trust bundle to be written to
+	// the target. The bundle is created deterministically, so it only changes
+	// when the certificates in the bundle or the password change.
+	// +optional
+	PKCS12 *PKCS12 `json:"pkcs12,omitempty"`
+}
+
+// JKS specifies configuration for a JKS-formatted trust bundle.
+type JKS struct {
+	KeySelector `json:",inline"`
+
+	// Password for the JKS trust store. Defaults to "changeit".
+	// Must not be set when passwordSecretRef is set.
+	// +optional
+	Password *string `json:"password,omitempty"`
+
+	// PasswordSecretRef is a reference to a key in a Secret in the trust
+	// Namespace holding the password for the JKS trust store.
+	// Must not be set when password is set.
+	// +optional
+	PasswordSecretRef *SecretKeySelector `json:"passwordSecretRef,omitempty"`
+}
+
+// PKCS12 specifies configuration for a PKCS12-formatted trust bundle.
+type PKCS12 struct {
+	KeySelector `json:",inline"`
+
+	// Password for the PKCS12 trust store. By default, the trust store is
+	// created without a password. Trust stores with a password are encrypted
+	// with AES-256 and require Java 12, OpenSSL 1.1.1 or newer to read.
+	// Must not be set when passwordSecretRef is set.
+	// +optional
+	Password *string `json:"password,omitempty"`
+
+	// PasswordSecretRef is a reference to a key in a Secret in the trust
+	// Namespace holding the password for the PKCS12 trust store.
+	// Must not be set when password is set.
+	// +optional
+	PasswordSecretRef *SecretKeySelector `json:"passwordSecretRef,omitempty"`
 }
 
 // NamespaceSelector defines selectors to match on Namespaces.
@@ -119,18 +469,74 @@ type NamespaceSelector struct {
 	// Namespace for the Bundle target to be synced there.
 	// +optional
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// MatchExpressions is a list of label selector requirements which must
+	// all be met by the labels of a Namespace for the Bundle target to be
+	// synced there.
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+
+	// IncludeNames, if set, is the list of names of the only Namespaces
+	// which the Bundle target is synced to.
+	// +optional
+	IncludeNames []string `json:"includeNames,omitempty"`
+
+	// ExcludeNames is a list of names of Namespaces which the Bundle target
+	// is never synced to.
+	// +optional
+	ExcludeNames []string `json:"excludeNames,omitempty"`
+}
+
+// BundleReference is a reference to another Bundle.
+type BundleReference struct {
+	// Name is the name of the referenced Bundle.
+	Name string `json:"name"`
+}
+
+// URLSource is a reference to a PEM-encoded bundle published at an HTTPS URL.
+type URLSource struct {
+	// Address is the HTTPS URL that the bundle is fetched from.
+	Address string `json:"address"`
+
+	// RefreshInterval is how often the bundle is fetched from the URL.
+	// Defaults to 1 hour. Must be at least 1 minute.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// SHA256 is an optional hex-encoded SHA-256 digest that the fetched data
+	// must match. If the fetched data doesn't match the digest, it is not used.
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
 }
 
 // SourceObjectKeySelector is a reference to a source object and its `data` key
 // in the trust Namespace.
 type SourceObjectKeySelector struct {
 	// Name is the name of the source object in the trust Namespace.
-	Name string `json:"name"`
+	// This field must be left empty when `selector` is set.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector is the label selector to use to fetch a list of objects in the
+	// trust Namespace. The data at the key of every matching object is
+	// appended to the source data, ordered by object name.
+	// This field must not be set when `name` is set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 
 	// KeySelector is the key of the entry in the objects' `data` field to be referenced.
 	KeySelector `json:",inline"`
 }
 
+// SecretKeySelector is a reference to a key of a Secret in the trust Namespace.
+type SecretKeySelector struct {
+	// Name is the name of the Secret in the trust Namespace.
+	Name string `json:"name"`
+
+	// KeySelector is the key of the entry in the Secret's `data` field to be referenced.
+	KeySelector `json:",inline"`
+}
+
 // KeySelector is a reference to a key for some map data object.
 type KeySelector struct {
 	// Key is the key of the entry in the object's `data` field to be used.
@@ -145,7 +551,7 @@ type BundleStatus struct {
 	Target *BundleTarget `json:"target"`
 
 	// List of status conditions to indicate the status of the Bundle.
-	// Known condition types are `Bundle`.
+	// Known condition types are `Synced`, `Degraded` and `CertificatesExpiringSoon`.
 	// +optional
 	Conditions []BundleCondition `json:"conditions,omitempty"`
 
@@ -154,11 +560,127 @@ type BundleStatus struct {
 	// source. This should only be set if useDefaultCAs was set to "true" on a source,
 	// and will be the same for the same version of a bundle with identical certificates.
 	DefaultCAPackageVersion *string `json:"defaultCAVersion,omitempty"`
+
+	// RemovedCertificates lists the certificates which were removed from the
+	// source data by the Bundle filter or crypto policy.
+	// +optional
+	RemovedCertificates []RemovedCertificate `json:"removedCertificates,omitempty"`
+
+	// FilterRules reports the number of certificates removed by each include
+	// and exclude rule of the Bundle filter.
+	// +optional
+	FilterRules []FilterRuleStatus `json:"filterRules,omitempty"`
+
+	// Namespaces reports the number of Namespaces which the Bundle targets
+	// were synced to, skipped or failed to sync to in the last sync, and
+	// which Namespaces failed.
+	// +optional
+	Namespaces *NamespaceSyncStatus `json:"namespaces,omitempty"`
+
+	// Certificates summarises the certificates in the bundle which is synced
+	// to the targets.
+	// +optional
+	Certificates *CertificatesStatus `json:"certificates,omitempty"`
+}
+
+// CertificatesStatus summarises the certificates in a bundle.
+type CertificatesStatus struct {
+	// Count is the number of certificates in the bundle.
+	Count int32 `json:"count"`
+
+	// EarliestExpiry is the earliest NotAfter time of any certificate in the
+	// bundle.
+	EarliestExpiry metav1.Time `json:"earliestExpiry"`
+
+	// EarliestExpirySubject is the subject distinguished name of the
+	// certificate in the bundle which expires first.
+	EarliestExpirySubject string `json:"earliestExpirySubject"`
+
+	// SHA256 is the hex-encoded SHA-256 hash of the PEM-encoded bundle.
+	SHA256 string `json:"sha256"`
+
+	// Sources summarises the certificates in the data of each source, before
+	// duplicates are removed and the Bundle filter and crypto policy are
+	// applied.
+	// +optional
+	Sources []SourceCertificatesStatus `json:"sources,omitempty"`
+}
+
+// SourceCertificatesStatus summarises the certificates in the data of a
+// single source of a Bundle.
+type SourceCertificatesStatus struct {
+	// Source identifies the source by its index in the Bundle sources, for
+	// example `sources[0]`.
+	Source string `json:"source"`
+
+	// Count is the number of certificates in the source data.
+	Count int32 `json:"count"`
+
+	// EarliestExpiry is the earliest NotAfter time of any certificate in the
+	// source data.
+	EarliestExpiry metav1.Time `json:"earliestExpiry"`
+}
+
+// NamespaceSyncStatus is the result of syncing the Bundle targets to all
+// Namespaces.
+type NamespaceSyncStatus struct {
+	// Synced is the number of Namespaces which the ConfigMap or Secret targets
+	// were synced to. Namespaces are only counted if the Bundle has such a
+	// target.
+	Synced int32 `json:"synced"`
+
+	// Skipped is the number of Namespaces which are not targeted, because
+	// they don't match the namespace selector or are being terminated.
+	Skipped int32 `json:"skipped"`
+
+	// Failed is the number of Namespaces which the targets failed to sync to.
+	Failed int32 `json:"failed"`
+
+	// FailedNamespaces lists the Namespaces which the targets failed to sync
+	// to and why, ordered by name. At most 10 Namespaces are listed.
+	// +optional
+	FailedNamespaces []NamespaceSyncFailure `json:"failedNamespaces,omitempty"`
+}
+
+// NamespaceSyncFailure is a Namespace which the Bundle targets failed to sync
+// to.
+type NamespaceSyncFailure struct {
+	// Namespace is the name of the Namespace.
+	Namespace string `json:"namespace"`
+
+	// Message is the error which occurred when syncing the targets.
+	Message string `json:"message"`
+}
+
+// FilterRuleStatus is the number of certificates removed by a rule of the
+// Bundle filter.
+type FilterRuleStatus struct {
+	// Rule identifies the rule, for example `exclude[0]`. Certificates which
+	// match none of the include rules are reported for the rule `include`.
+	Rule string `json:"rule"`
+
+	// Removed is the number of certificates removed by the rule.
+	Removed int32 `json:"removed"`
+}
+
+// RemovedCertificate is a certificate which was removed from the source data
+// of a Bundle.
+type RemovedCertificate struct {
+	// Subject is the subject distinguished name of the certificate.
+	Subject string `json:"subject"`
+
+	// SHA256 is the hex-encoded SHA-256 fingerprint of the DER-encoded
+	// certificate.
+	SHA256 string `json:"sha256"`
+
+	// Reason is a brief machine readable explanation for why the certificate
+	// was removed.
+	Reason string `json:"reason"`
 }
 
 // BundleCondition contains condition information for a Bundle.
 type BundleCondition struct {
-	// Type of the condition, known values are (`Synced`).
+	// Type of the condition, known values are (`Synced`, `Degraded`, `CertificatesExpiringSoon`).
 	Type BundleConditionType `json:"type"`
 
 	// Status of the condition, one of ('True', 'False', 'Unknown').
@@ -195,4 +717,14 @@ const (
 	// BundleConditionSynced indicates that the Bundle has successfully synced
 	// all source bundle data to the Bundle target in all Namespaces.
 	BundleConditionSynced BundleConditionType = "Synced"
+
+	// BundleConditionDegraded indicates that the data of one or more Bundle
+	// sources could not be refreshed, and that the last known good data is
+	// being used instead.
+	BundleConditionDegraded BundleConditionType = "Degraded"
+
+	// BundleConditionCertificatesExpiringSoon indicates that one or more
+	// certificates in the Bundle have expired or expire within the expiry
+	// warning window of trust-manager.
+	BundleConditionCertificatesExpiringSoon BundleConditionType = "CertificatesExpiringSoon"
 )
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,8 +31,13 @@ func (in *AdditionalFormats) DeepCopyInto(out *AdditionalFormats) {
 	*out = *in
 	if in.JKS != nil {
 		in, out := &in.JKS, &out.JKS
-		*out = new(KeySelector)
-		**out = **in
+		*out = new(JKS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PKCS12 != nil {
+		in, out := &in.PKCS12, &out.PKCS12
+		*out = new(PKCS12)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -94,6 +100,37 @@ func (in *BundleCondition) DeepCopy() *BundleCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleFilter) DeepCopyInto(out *BundleFilter) {
+	*out = *in
+	if in.EnforceValidityPeriod != nil {
+		in, out := &in.EnforceValidityPeriod, &out.EnforceValidityPeriod
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]CertificateMatcher, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]CertificateMatcher, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleFilter.
+func (in *BundleFilter) DeepCopy() *BundleFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BundleList) DeepCopyInto(out *BundleList) {
 	*out = *in
@@ -127,24 +164,55 @@ func (in *BundleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleReference) DeepCopyInto(out *BundleReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleReference.
+func (in *BundleReference) DeepCopy() *BundleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BundleSource) DeepCopyInto(out *BundleSource) {
 	*out = *in
 	if in.ConfigMap != nil {
 		in, out := &in.ConfigMap, &out.ConfigMap
 		*out = new(SourceObjectKeySelector)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Secret != nil {
 		in, out := &in.Secret, &out.Secret
 		*out = new(SourceObjectKeySelector)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.InLine != nil {
 		in, out := &in.InLine, &out.InLine
 		*out = new(string)
 		**out = **in
 	}
+	if in.Bundle != nil {
+		in, out := &in.Bundle, &out.Bundle
+		*out = new(BundleReference)
+		**out = **in
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(URLSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterTrustBundle != nil {
+		in, out := &in.ClusterTrustBundle, &out.ClusterTrustBundle
+		*out = new(ClusterTrustBundleSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UseDefaultCAs != nil {
 		in, out := &in.UseDefaultCAs, &out.UseDefaultCAs
 		*out = new(bool)
@@ -174,6 +242,16 @@ func (in *BundleSpec) DeepCopyInto(out *BundleSpec) {
 		}
 	}
 	in.Target.DeepCopyInto(&out.Target)
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(BundleFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CryptoPolicy != nil {
+		in, out := &in.CryptoPolicy, &out.CryptoPolicy
+		*out = new(CryptoPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -207,6 +285,26 @@ func (in *BundleStatus) DeepCopyInto(out *BundleStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RemovedCertificates != nil {
+		in, out := &in.RemovedCertificates, &out.RemovedCertificates
+		*out = make([]RemovedCertificate, len(*in))
+		copy(*out, *in)
+	}
+	if in.FilterRules != nil {
+		in, out := &in.FilterRules, &out.FilterRules
+		*out = make([]FilterRuleStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = new(NamespaceSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(CertificatesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -225,19 +323,41 @@ func (in *BundleTarget) DeepCopyInto(out *BundleTarget) {
 	*out = *in
 	if in.ConfigMap != nil {
 		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapTarget)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
 		*out = new(KeySelector)
 		**out = **in
 	}
+	if in.ClusterTrustBundle != nil {
+		in, out := &in.ClusterTrustBundle, &out.ClusterTrustBundle
+		*out = new(ClusterTrustBundleTarget)
+		**out = **in
+	}
 	if in.AdditionalFormats != nil {
 		in, out := &in.AdditionalFormats, &out.AdditionalFormats
 		*out = new(AdditionalFormats)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]TargetKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NamespaceSelector != nil {
 		in, out := &in.NamespaceSelector, &out.NamespaceSelector
 		*out = new(NamespaceSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(TargetMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -251,6 +371,173 @@ func (in *BundleTarget) DeepCopy() *BundleTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMatcher) DeepCopyInto(out *CertificateMatcher) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMatcher.
+func (in *CertificateMatcher) DeepCopy() *CertificateMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatesStatus) DeepCopyInto(out *CertificatesStatus) {
+	*out = *in
+	in.EarliestExpiry.DeepCopyInto(&out.EarliestExpiry)
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SourceCertificatesStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesStatus.
+func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTrustBundleSource) DeepCopyInto(out *ClusterTrustBundleSource) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTrustBundleSource.
+func (in *ClusterTrustBundleSource) DeepCopy() *ClusterTrustBundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTrustBundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTrustBundleTarget) DeepCopyInto(out *ClusterTrustBundleTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTrustBundleTarget.
+func (in *ClusterTrustBundleTarget) DeepCopy() *ClusterTrustBundleTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTrustBundleTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapTarget) DeepCopyInto(out *ConfigMapTarget) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapTarget.
+func (in *ConfigMapTarget) DeepCopy() *ConfigMapTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CryptoPolicy) DeepCopyInto(out *CryptoPolicy) {
+	*out = *in
+	if in.MinRSAKeySize != nil {
+		in, out := &in.MinRSAKeySize, &out.MinRSAKeySize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedCurves != nil {
+		in, out := &in.AllowedCurves, &out.AllowedCurves
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenSignatureAlgorithms != nil {
+		in, out := &in.ForbiddenSignatureAlgorithms, &out.ForbiddenSignatureAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CryptoPolicy.
+func (in *CryptoPolicy) DeepCopy() *CryptoPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CryptoPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterRuleStatus) DeepCopyInto(out *FilterRuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterRuleStatus.
+func (in *FilterRuleStatus) DeepCopy() *FilterRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JKS) DeepCopyInto(out *JKS) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JKS.
+func (in *JKS) DeepCopy() *JKS {
+	if in == nil {
+		return nil
+	}
+	out := new(JKS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeySelector) DeepCopyInto(out *KeySelector) {
 	*out = *in
@@ -277,6 +564,23 @@ func (in *NamespaceSelector) DeepCopyInto(out *NamespaceSelector) {
 			(*out)[key] = val
 		}
 	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]v1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IncludeNames != nil {
+		in, out := &in.IncludeNames, &out.IncludeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeNames != nil {
+		in, out := &in.ExcludeNames, &out.ExcludeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -290,9 +594,128 @@ func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSyncFailure) DeepCopyInto(out *NamespaceSyncFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSyncFailure.
+func (in *NamespaceSyncFailure) DeepCopy() *NamespaceSyncFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSyncFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSyncStatus) DeepCopyInto(out *NamespaceSyncStatus) {
+	*out = *in
+	if in.FailedNamespaces != nil {
+		in, out := &in.FailedNamespaces, &out.FailedNamespaces
+		*out = make([]NamespaceSyncFailure, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSyncStatus.
+func (in *NamespaceSyncStatus) DeepCopy() *NamespaceSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PKCS12) DeepCopyInto(out *PKCS12) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PKCS12.
+func (in *PKCS12) DeepCopy() *PKCS12 {
+	if in == nil {
+		return nil
+	}
+	out := new(PKCS12)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemovedCertificate) DeepCopyInto(out *RemovedCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemovedCertificate.
+func (in *RemovedCertificate) DeepCopy() *RemovedCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(RemovedCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceCertificatesStatus) DeepCopyInto(out *SourceCertificatesStatus) {
+	*out = *in
+	in.EarliestExpiry.DeepCopyInto(&out.EarliestExpiry)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceCertificatesStatus.
+func (in *SourceCertificatesStatus) DeepCopy() *SourceCertificatesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceCertificatesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SourceObjectKeySelector) DeepCopyInto(out *SourceObjectKeySelector) {
 	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	out.KeySelector = in.KeySelector
 	return
 }
@@ -306,3 +729,81 @@ func (in *SourceObjectKeySelector) DeepCopy() *SourceObjectKeySelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetKey) DeepCopyInto(out *TargetKey) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetKey.
+func (in *TargetKey) DeepCopy() *TargetKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetMetadata) DeepCopyInto(out *TargetMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetMetadata.
+func (in *TargetMetadata) DeepCopy() *TargetMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *URLSource) DeepCopyInto(out *URLSource) {
+	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new URLSource.
+func (in *URLSource) DeepCopy() *URLSource {
+	if in == nil {
+		return nil
+	}
+	out := new(URLSource)
+	in.DeepCopyInto(out)
+	return out
+}
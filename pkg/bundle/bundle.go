@@ -20,8 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,8 +40,13 @@ import (
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/pkg/util"
 )
 
+// maxFailedNamespaces is the maximum number of Namespaces which are listed in
+// the Bundle status as having failed to sync.
+const maxFailedNamespaces = 10
+
 // Options hold options for the Bundle controller.
 type Options struct {
 	// Log is the Bundle controller logger.
@@ -49,6 +60,26 @@ type Options struct {
 	// loaded in order for the controller to start. If unset, referring to the default
 	// certificate package in a `Bundle` resource will cause that Bundle to error.
 	DefaultPackageLocation string
+
+	// SecretTargetsEnabled controls whether Bundles may use Secrets as
+	// targets. Writing Secrets requires trust-manager to have permissions to
+	// manage Secrets in all Namespaces, so this is disabled by default.
+	SecretTargetsEnabled bool
+
+	// ClusterTrustBundleTargetsEnabled controls whether Bundles may use
+	// ClusterTrustBundles as targets. ClusterTrustBundles are an alpha API
+	// which must be enabled in the cluster, so this is disabled by default.
+	ClusterTrustBundleTargetsEnabled bool
+
+	// ClusterTrustBundleSourcesEnabled controls whether Bundles may use
+	// ClusterTrustBundles as sources. ClusterTrustBundles are an alpha API
+	// which must be enabled in the cluster, so this is disabled by default.
+	ClusterTrustBundleSourcesEnabled bool
+
+	// ExpiryWarningWindow is the window before expiry in which certificates
+	// in a Bundle cause the CertificatesExpiringSoon condition to be set and
+	// a Warning event to be emitted. Zero disables the warning.
+	ExpiryWarningWindow time.Duration
 }
 
 // bundle is a controller-runtime controller. Implements the actual controller
@@ -70,9 +101,20 @@ type bundle struct {
 	// recorder is used for create Kubernetes Events for reconciled Bundles.
 	recorder record.EventRecorder
 
+	// httpClient is used to fetch the data of URL sources.
+	httpClient *http.Client
+
+	// urlFetches holds the time at which each URL source was last fetched
+	// without changing the data in its cache ConfigMap, keyed by address.
+	urlFetches     map[string]time.Time
+	urlFetchesLock sync.Mutex
+
 	// clock returns time which can be overwritten for testing.
 	clock clock.Clock
 
+	// metrics exposes the contents and sync health of Bundles to Prometheus.
+	metrics *metricsCollector
+
 	// Options holds options for the Bundle controller.
 	Options
 }
@@ -88,6 +130,14 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 	err := b.sourceLister.Get(ctx, req.NamespacedName, &bundle)
 	if apierrors.IsNotFound(err) {
 		log.V(2).Info("bundle no longer exists, ignoring")
+		b.metrics.forgetBundle(req.Name)
+
+		// The Bundle may have been the last one using a URL source.
+		if err := b.pruneURLCaches(ctx); err != nil {
+			log.Error(err, "failed to prune URL caches")
+			return ctrl.Result{}, err
+		}
+
 		return ctrl.Result{}, nil
 	}
 
@@ -96,13 +146,54 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, fmt.Errorf("failed to get %q: %s", req.NamespacedName, err)
 	}
 
-	namespaceSelector := labels.Everything()
-	if nsSelector := bundle.Spec.Target.NamespaceSelector; nsSelector != nil && nsSelector.MatchLabels != nil {
-		namespaceSelector, err = metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: nsSelector.MatchLabels})
-		if err != nil {
-			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "NamespaceSelectorError", "Failed to build namespace match labels selector: %s", err)
-			return ctrl.Result{}, fmt.Errorf("failed to build NamespaceSelector: %w", err)
-		}
+	start := b.clock.Now()
+	defer func() {
+		b.metrics.observeSyncDuration(bundle.Name, b.clock.Since(start))
+	}()
+
+	if bundle.Spec.Target.Secret != nil && !b.SecretTargetsEnabled {
+		log.Error(errors.New("secret targets are disabled"), "bundle has a Secret target but Secret targets are not enabled")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SecretTargetsDisabled",
+			Message: "Bundle has a Secret target but Secret targets are not enabled in trust-manager",
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SecretTargetsDisabled", "Bundle has a Secret target but Secret targets are not enabled in trust-manager")
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	if bundle.Spec.Target.ClusterTrustBundle != nil && !b.ClusterTrustBundleTargetsEnabled {
+		log.Error(errors.New("cluster trust bundle targets are disabled"), "bundle has a ClusterTrustBundle target but ClusterTrustBundle targets are not enabled")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ClusterTrustBundleTargetsDisabled",
+			Message: "Bundle has a ClusterTrustBundle target but ClusterTrustBundle targets are not enabled in trust-manager",
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "ClusterTrustBundleTargetsDisabled", "Bundle has a ClusterTrustBundle target but ClusterTrustBundle targets are not enabled in trust-manager")
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	if bundleHasClusterTrustBundleSource(&bundle) && !b.ClusterTrustBundleSourcesEnabled {
+		log.Error(errors.New("cluster trust bundle sources are disabled"), "bundle has a ClusterTrustBundle source but ClusterTrustBundle sources are not enabled")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ClusterTrustBundleSourcesDisabled",
+			Message: "Bundle has a ClusterTrustBundle source but ClusterTrustBundle sources are not enabled in trust-manager",
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "ClusterTrustBundleSourcesDisabled", "Bundle has a ClusterTrustBundle source but ClusterTrustBundle sources are not enabled in trust-manager")
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	namespaceSelector, err := util.NamespaceSelector(bundle.Spec.Target.NamespaceSelector)
+	if err != nil {
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "NamespaceSelectorError", "Failed to build namespace selector: %s", err)
+		return ctrl.Result{}, fmt.Errorf("failed to build NamespaceSelector: %w", err)
 	}
 
 	var namespaceList corev1.NamespaceList
@@ -117,37 +208,23 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		log.Info("deleting old targets", "old_target", bundle.Status.Target)
 		b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "DeleteOldTarget", "Deleting old targets as Bundle target has been modified")
 
-		for _, namespace := range namespaceList.Items {
-			configMap := &corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      bundle.Name,
-					Namespace: namespace.Name,
-				},
-			}
-
-			err := b.targetDirectClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
-
-			// Ignore ConfigMaps that have not been created yet, as they will be
-			// created later on in the sync.
-			if apierrors.IsNotFound(err) {
-				continue
-			}
-
-			if err != nil {
-				log.Error(err, "failed to get target ConfigMap")
-				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetGetError", "Failed to get target ConfigMap: %s", err)
-				return ctrl.Result{}, fmt.Errorf("failed to get target ConfigMap: %w", err)
+		if bundle.Status.Target.ClusterTrustBundle != nil {
+			if err := b.deleteOldClusterTrustBundleTarget(ctx, log, &bundle); err != nil {
+				return ctrl.Result{}, err
 			}
+		}
 
-			delete(configMap.Data, bundle.Status.Target.ConfigMap.Key)
-			if bundle.Status.Target.AdditionalFormats != nil && bundle.Status.Target.AdditionalFormats.JKS != nil {
-				delete(configMap.BinaryData, bundle.Status.Target.AdditionalFormats.JKS.Key)
+		for _, namespace := range namespaceList.Items {
+			if bundle.Status.Target.ConfigMap != nil {
+				if err := b.deleteOldConfigMapTarget(ctx, log, &bundle, namespace.Name); err != nil {
+					return ctrl.Result{}, err
+				}
 			}
 
-			if err := b.targetDirectClient.Update(ctx, configMap); err != nil {
-				log.Error(err, "failed to delete old ConfigMap target key")
-				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetUpdateError", "Failed to remove old key from ConfigMap target: %s", err)
-				return ctrl.Result{}, fmt.Errorf("failed to delete old ConfigMap target key: %w", err)
+			if bundle.Status.Target.Secret != nil {
+				if err := b.deleteOldSecretTarget(ctx, log, &bundle, namespace.Name); err != nil {
+					return ctrl.Result{}, err
+				}
 			}
 
 			log.V(2).Info("deleted old target key", "old_target", bundle.Status.Target, "namespace", namespace.Name)
@@ -158,6 +235,12 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
 	}
 
+	// A URL source may have been removed from the Bundle. Failing to prune
+	// unused caches doesn't affect the Bundle, so it is synced regardless.
+	if err := b.pruneURLCaches(ctx); err != nil {
+		log.Error(err, "failed to prune URL caches")
+	}
+
 	resolvedBundle, err := b.buildSourceBundle(ctx, &bundle)
 
 	// If any source is not found, update the Bundle status to an unready state.
@@ -174,41 +257,225 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
 	}
 
+	// If Bundle sources reference each other in a cycle, the Bundle can never
+	// be resolved so update the Bundle status to an unready state.
+	if errors.As(err, &cycleError{}) {
+		log.Error(err, "bundle sources reference each other in a cycle")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SourceCycleDetected",
+			Message: "Bundle sources reference each other in a cycle: " + err.Error(),
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SourceCycleDetected", "Bundle sources reference each other in a cycle: %s", err)
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	// If certificates violate the crypto policy of the Bundle in Fail mode,
+	// update the Bundle status to an unready state.
+	if errors.As(err, &cryptoPolicyError{}) {
+		log.Error(err, "bundle certificates violate the crypto policy")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "CryptoPolicyViolation",
+			Message: "Bundle certificates violate the crypto policy: " + err.Error(),
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "CryptoPolicyViolation", "Bundle certificates violate the crypto policy: %s", err)
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	// If certificates don't meet the CA requirement of the Bundle, update the
+	// Bundle status to an unready state.
+	if errors.As(err, &caRequirementError{}) {
+		log.Error(err, "bundle certificates do not meet the CA requirement")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "CARequirementViolation",
+			Message: "Bundle certificates do not meet the CA requirement: " + err.Error(),
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "CARequirementViolation", "Bundle certificates do not meet the CA requirement: %s", err)
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
 	if err != nil {
 		log.Error(err, "failed to build source bundle")
 		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SourceBuildError", "Failed to build bundle sources: %s", err)
 		return ctrl.Result{}, fmt.Errorf("failed to build bundle source: %w", err)
 	}
 
+	resolvedTarget, err := b.buildTargetData(ctx, &bundle, resolvedBundle.data)
+
+	// If a trust store password Secret is not found, update the Bundle status
+	// to an unready state.
+	if errors.As(err, &notFoundError{}) {
+		log.Error(err, "bundle target password was not found")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "PasswordNotFound",
+			Message: "Bundle target password was not found: " + err.Error(),
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PasswordNotFound", "Bundle target password was not found: %s", err)
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	if err != nil {
+		log.Error(err, "failed to build target data")
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetBuildError", "Failed to build bundle target data: %s", err)
+		return ctrl.Result{}, fmt.Errorf("failed to build bundle target data: %w", err)
+	}
+
 	var needsUpdate bool
-	for _, namespace := range namespaceList.Items {
-		log = log.WithValues("namespace", namespace.Name)
 
-		// Don't reconcile target for Namespaces that are being terminated.
-		if namespace.Status.Phase == corev1.NamespaceTerminating {
-			log.V(2).WithValues("phase", corev1.NamespaceTerminating).Info("skipping sync for namespace as it is terminating")
-			continue
+	if len(resolvedBundle.degradedSources) > 0 {
+		degradedCondition := trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionDegraded,
+			Status:  corev1.ConditionTrue,
+			Reason:  "SourceRefreshFailed",
+			Message: "Using last known good data for sources which failed to refresh: " + strings.Join(resolvedBundle.degradedSources, "; "),
+		}
+
+		if !bundleHasCondition(&bundle, degradedCondition) {
+			log.Info("using last known good data for sources which failed to refresh", "sources", resolvedBundle.degradedSources)
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SourceRefreshFailed", "%s", degradedCondition.Message)
+			b.setBundleCondition(&bundle, degradedCondition)
+			needsUpdate = true
+		}
+	} else if removeBundleCondition(&bundle, trustapi.BundleConditionDegraded) {
+		needsUpdate = true
+	}
+
+	if expiring := b.expiringCertificates(&resolvedBundle); len(expiring) > 0 {
+		expiringCondition := trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionCertificatesExpiringSoon,
+			Status:  corev1.ConditionTrue,
+			Reason:  "CertificatesExpiringSoon",
+			Message: fmt.Sprintf("Bundle contains certificates which expire within %s: %s", b.ExpiryWarningWindow, strings.Join(expiring, "; ")),
+		}
+
+		if !bundleHasCondition(&bundle, expiringCondition) {
+			log.Info("bundle contains certificates which expire soon", "certificates", expiring)
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "CertificatesExpiringSoon", "%s", expiringCondition.Message)
+			b.setBundleCondition(&bundle, expiringCondition)
+			needsUpdate = true
+		}
+	} else if removeBundleCondition(&bundle, trustapi.BundleConditionCertificatesExpiringSoon) {
+		needsUpdate = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(bundle.Status.RemovedCertificates, resolvedBundle.removedCertificates) {
+		log.Info("certificates removed from bundle by filter or crypto policy changed", "count", len(resolvedBundle.removedCertificates))
+
+		if len(resolvedBundle.cryptoPolicyViolations) > 0 {
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "CryptoPolicyViolation", "Removed certificates which violate the crypto policy: %s", strings.Join(resolvedBundle.cryptoPolicyViolations, "; "))
 		}
 
-		synced, err := b.syncTarget(ctx, log, &bundle, namespaceSelector, &namespace, resolvedBundle.data)
+		bundle.Status.RemovedCertificates = resolvedBundle.removedCertificates
+		needsUpdate = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(bundle.Status.FilterRules, resolvedBundle.filterRules) {
+		bundle.Status.FilterRules = resolvedBundle.filterRules
+		needsUpdate = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(bundle.Status.Certificates, resolvedBundle.certificates) {
+		bundle.Status.Certificates = resolvedBundle.certificates
+		needsUpdate = true
+	}
+
+	// Resolve the Bundle again when any of its sources need to be refreshed,
+	// or when the validity of any of its certificates changes.
+	var result ctrl.Result
+	if !resolvedBundle.nextRefresh.IsZero() {
+		result.RequeueAfter = resolvedBundle.nextRefresh.Sub(b.clock.Now())
+	}
+
+	if bundle.Spec.Target.ClusterTrustBundle != nil {
+		synced, err := b.syncClusterTrustBundleTarget(ctx, log, &bundle, resolvedTarget)
 		if err != nil {
-			log.Error(err, "failed sync bundle to target namespace")
-			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncTargetFailed", "Failed to sync target in Namespace %q: %s", namespace.Name, err)
+			log.Error(err, "failed sync bundle to target ClusterTrustBundle")
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncTargetFailed", "Failed to sync ClusterTrustBundle target: %s", err)
 
 			b.setBundleCondition(&bundle, trustapi.BundleCondition{
 				Type:    trustapi.BundleConditionSynced,
 				Status:  corev1.ConditionFalse,
 				Reason:  "SyncTargetFailed",
-				Message: fmt.Sprintf("Failed to sync bundle to namespace %q: %s", namespace.Name, err),
+				Message: "Failed to sync bundle to ClusterTrustBundle: " + err.Error(),
 			})
 
 			return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
 		}
 
+		if synced {
+			needsUpdate = true
+		}
+	}
+
+	// Sync the targets to every Namespace, even if syncing to some of them
+	// fails, so that a single broken Namespace doesn't block all others.
+	// Namespaces are still visited without a namespaced target, so that old
+	// targets are removed, but they are only counted as synced or skipped
+	// if the Bundle has a ConfigMap or Secret target.
+	hasNamespacedTarget := bundle.Spec.Target.ConfigMap != nil || bundle.Spec.Target.Secret != nil
+	namespaceStatus := &trustapi.NamespaceSyncStatus{}
+	for _, namespace := range namespaceList.Items {
+		log := log.WithValues("namespace", namespace.Name)
+
+		// Don't reconcile target for Namespaces that are being terminated.
+		if namespace.Status.Phase == corev1.NamespaceTerminating {
+			log.V(2).WithValues("phase", corev1.NamespaceTerminating).Info("skipping sync for namespace as it is terminating")
+			if hasNamespacedTarget {
+				namespaceStatus.Skipped++
+			}
+			continue
+		}
+
+		synced, err := b.syncTargets(ctx, log, &bundle, namespaceSelector, &namespace, resolvedTarget)
 		if synced {
 			// We need to update if any target is synced.
 			needsUpdate = true
 		}
+
+		if err != nil {
+			log.Error(err, "failed sync bundle to target namespace")
+			namespaceStatus.Failed++
+			namespaceStatus.FailedNamespaces = append(namespaceStatus.FailedNamespaces, trustapi.NamespaceSyncFailure{
+				Namespace: namespace.Name,
+				Message:   err.Error(),
+			})
+			continue
+		}
+
+		if !hasNamespacedTarget {
+			continue
+		}
+
+		if namespaceSelector.Matches(util.NamespaceLabels(&namespace)) {
+			namespaceStatus.Synced++
+		} else {
+			namespaceStatus.Skipped++
+		}
+	}
+
+	sort.Slice(namespaceStatus.FailedNamespaces, func(i, j int) bool {
+		return namespaceStatus.FailedNamespaces[i].Namespace < namespaceStatus.FailedNamespaces[j].Namespace
+	})
+	if len(namespaceStatus.FailedNamespaces) > maxFailedNamespaces {
+		namespaceStatus.FailedNamespaces = namespaceStatus.FailedNamespaces[:maxFailedNamespaces]
+	}
+
+	b.metrics.observeBundle(bundle.Name, resolvedBundle.parsedCertificates, namespaceStatus)
+
+	if !apiequality.Semantic.DeepEqual(bundle.Status.Namespaces, namespaceStatus) {
+		bundle.Status.Namespaces = namespaceStatus
+		needsUpdate = true
 	}
 
 	if bundle.Status.Target == nil || !apiequality.Semantic.DeepEqual(*bundle.Status.Target, bundle.Spec.Target) {
@@ -216,14 +483,37 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		needsUpdate = true
 	}
 
+	// If syncing to any Namespace failed, update the Bundle status to an
+	// unready state and retry. Targets in all other Namespaces are synced.
+	if namespaceStatus.Failed > 0 {
+		var failed []string
+		for _, failure := range namespaceStatus.FailedNamespaces {
+			failed = append(failed, fmt.Sprintf("%q: %s", failure.Namespace, failure.Message))
+		}
+		if more := int(namespaceStatus.Failed) - len(failed); more > 0 {
+			failed = append(failed, fmt.Sprintf("and %d more", more))
+		}
+
+		message := fmt.Sprintf("Failed to sync bundle to %d namespaces: %s", namespaceStatus.Failed, strings.Join(failed, "; "))
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncTargetFailed", "%s", message)
+
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SyncTargetFailed",
+			Message: message,
+		})
+
+		return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
 	if b.setBundleStatusDefaultCAVersion(&bundle, resolvedBundle.defaultCAPackageStringID) {
 		needsUpdate = true
 	}
 
 	message := "Successfully synced Bundle to all namespaces"
-	if nsSelector := bundle.Spec.Target.NamespaceSelector; nsSelector != nil && nsSelector.MatchLabels != nil {
-		message = fmt.Sprintf("Successfully synced Bundle to namespaces with selector [matchLabels:%v]",
-			nsSelector.MatchLabels)
+	if selector := describeNamespaceSelector(bundle.Spec.Target.NamespaceSelector); len(selector) > 0 {
+		message = fmt.Sprintf("Successfully synced Bundle to namespaces with selector [%s]", selector)
 	}
 
 	syncedCondition := trustapi.BundleCondition{
@@ -234,7 +524,7 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 	}
 
 	if !needsUpdate && bundleHasCondition(&bundle, syncedCondition) {
-		return ctrl.Result{}, nil
+		return result, nil
 	}
 
 	log.V(2).Info("successfully synced bundle")
@@ -243,5 +533,274 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 
 	b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "Synced", message)
 
-	return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	return result, b.targetDirectClient.Status().Update(ctx, &bundle)
+}
+
+// bundleHasClusterTrustBundleSource returns true if any source of the given
+// Bundle references ClusterTrustBundles.
+func bundleHasClusterTrustBundleSource(bundle *trustapi.Bundle) bool {
+	for _, source := range bundle.Spec.Sources {
+		if source.ClusterTrustBundle != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// describeNamespaceSelector returns a human readable description of the
+// given namespace selector, or an empty string if it selects all Namespaces.
+func describeNamespaceSelector(nsSelector *trustapi.NamespaceSelector) string {
+	if nsSelector == nil {
+		return ""
+	}
+
+	var parts []string
+	if len(nsSelector.MatchLabels) > 0 {
+		parts = append(parts, fmt.Sprintf("matchLabels:%v", nsSelector.MatchLabels))
+	}
+
+	if len(nsSelector.MatchExpressions) > 0 {
+		expressions := make([]string, 0, len(nsSelector.MatchExpressions))
+		for _, expression := range nsSelector.MatchExpressions {
+			expressions = append(expressions, fmt.Sprintf("%s %s %v", expression.Key, expression.Operator, expression.Values))
+		}
+
+		parts = append(parts, fmt.Sprintf("matchExpressions:[%s]", strings.Join(expressions, ", ")))
+	}
+
+	if len(nsSelector.IncludeNames) > 0 {
+		parts = append(parts, fmt.Sprintf("includeNames:%v", nsSelector.IncludeNames))
+	}
+
+	if len(nsSelector.ExcludeNames) > 0 {
+		parts = append(parts, fmt.Sprintf("excludeNames:%v", nsSelector.ExcludeNames))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// syncTargets syncs the given data to all targets defined on the Bundle in the
+// given namespace. Returns true if any target has been created, updated or
+// deleted.
+func (b *bundle) syncTargets(ctx context.Context, log logr.Logger,
+	bundle *trustapi.Bundle,
+	namespaceSelector labels.Selector,
+	namespace *corev1.Namespace,
+	data targetData,
+) (bool, error) {
+	var synced bool
+
+	if bundle.Spec.Target.ConfigMap != nil {
+		configMapSynced, err := b.syncConfigMapTarget(ctx, log, bundle, namespaceSelector, namespace, data)
+		if err != nil {
+			return configMapSynced, err
+		}
+
+		synced = synced || configMapSynced
+	}
+
+	if bundle.Spec.Target.Secret != nil {
+		secretSynced, err := b.syncSecretTarget(ctx, log, bundle, namespaceSelector, namespace, data)
+		if err != nil {
+			return secretSynced, err
+		}
+
+		synced = synced || secretSynced
+	}
+
+	return synced, nil
+}
+
+// deleteOldConfigMapTarget removes the keys and metadata of the old ConfigMap
+// target stored in the Bundle status from the ConfigMap in the given
+// namespace. If the Bundle no longer targets ConfigMaps, or the target
+// ConfigMap has been renamed, the old ConfigMap is deleted entirely when it is
+// owned by the Bundle.
+func (b *bundle) deleteOldConfigMapTarget(ctx context.Context, log logr.Logger, bundle *trustapi.Bundle, namespace string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapTargetName(bundle, bundle.Status.Target),
+			Namespace: namespace,
+		},
+	}
+
+	err := b.targetDirectClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+
+	// Ignore ConfigMaps that have not been created yet, as they will be
+	// created later on in the sync.
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		log.Error(err, "failed to get target ConfigMap")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetGetError", "Failed to get target ConfigMap: %s", err)
+		return fmt.Errorf("failed to get target ConfigMap: %w", err)
+	}
+
+	// The ConfigMap was taken over by another Bundle, which now manages its
+	// fields with the same field manager; leave it alone.
+	if otherBundleController(configMap, bundle) != "" {
+		return nil
+	}
+
+	targeted := bundle.Spec.Target.ConfigMap != nil && configMapTargetName(bundle, &bundle.Spec.Target) == configMap.Name
+	if !targeted && metav1.IsControlledBy(configMap, bundle) {
+		if err := b.targetDirectClient.Delete(ctx, configMap); err != nil {
+			log.Error(err, "failed to delete old ConfigMap target")
+			b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old ConfigMap target: %s", err)
+			return fmt.Errorf("failed to delete old ConfigMap target: %w", err)
+		}
+
+		return nil
+	}
+
+	// Apply the ConfigMap again with only the fields of the new target, so
+	// that server-side apply removes the keys and metadata of the old target.
+	// Keys of the new target keep their current data until the target is
+	// synced, which writes it again as the hash annotation is removed. If the
+	// ConfigMap is no longer targeted, nothing is applied and all fields are
+	// released.
+	apply := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: configMap.Name, Namespace: namespace},
+	}
+
+	if targeted {
+		apply.ObjectMeta = targetObjectMeta(bundle, configMap.Name, namespace, "")
+
+		for _, key := range append([]string{bundle.Spec.Target.ConfigMap.Key}, targetKeys(&bundle.Spec.Target)...) {
+			if value, ok := configMap.Data[key]; ok {
+				if apply.Data == nil {
+					apply.Data = make(map[string]string)
+				}
+				apply.Data[key] = value
+			}
+
+			if value, ok := configMap.BinaryData[key]; ok {
+				if apply.BinaryData == nil {
+					apply.BinaryData = make(map[string][]byte)
+				}
+				apply.BinaryData[key] = value
+			}
+		}
+	}
+
+	if err := b.applyTarget(ctx, configMap, apply); err != nil {
+		log.Error(err, "failed to delete old ConfigMap target key")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetUpdateError", "Failed to remove old key from ConfigMap target: %s", err)
+		return fmt.Errorf("failed to delete old ConfigMap target key: %w", err)
+	}
+
+	return nil
+}
+
+// deleteOldSecretTarget removes the keys and metadata of the old Secret
+// target stored in the Bundle status from the Secret in the given namespace.
+// If the Bundle no longer targets Secrets, the Secret is deleted entirely when
+// it is owned by the Bundle.
+func (b *bundle) deleteOldSecretTarget(ctx context.Context, log logr.Logger, bundle *trustapi.Bundle, namespace string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bundle.Name,
+			Namespace: namespace,
+		},
+	}
+
+	err := b.targetDirectClient.Get(ctx, client.ObjectKeyFromObject(secret), secret)
+
+	// Ignore Secrets that have not been created yet, as they will be created
+	// later on in the sync.
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		log.Error(err, "failed to get target Secret")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetGetError", "Failed to get target Secret: %s", err)
+		return fmt.Errorf("failed to get target Secret: %w", err)
+	}
+
+	targeted := bundle.Spec.Target.Secret != nil
+	if !targeted && metav1.IsControlledBy(secret, bundle) {
+		if err := b.targetDirectClient.Delete(ctx, secret); err != nil {
+			log.Error(err, "failed to delete old Secret target")
+			b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old Secret target: %s", err)
+			return fmt.Errorf("failed to delete old Secret target: %w", err)
+		}
+
+		return nil
+	}
+
+	// Apply the Secret again with only the fields of the new target, so that
+	// server-side apply removes the keys and metadata of the old target. Keys
+	// of the new target keep their current data until the target is synced,
+	// which writes it again as the hash annotation is removed. If the Secret
+	// is no longer targeted, nothing is applied and all fields are released.
+	apply := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: namespace},
+	}
+
+	if targeted {
+		apply.ObjectMeta = targetObjectMeta(bundle, secret.Name, namespace, "")
+
+		for _, key := range append([]string{bundle.Spec.Target.Secret.Key}, targetKeys(&bundle.Spec.Target)...) {
+			if value, ok := secret.Data[key]; ok {
+				if apply.Data == nil {
+					apply.Data = make(map[string][]byte)
+				}
+				apply.Data[key] = value
+			}
+		}
+	}
+
+	if err := b.applyTarget(ctx, secret, apply); err != nil {
+		log.Error(err, "failed to delete old Secret target key")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetUpdateError", "Failed to remove old key from Secret target: %s", err)
+		return fmt.Errorf("failed to delete old Secret target key: %w", err)
+	}
+
+	return nil
+}
+
+// deleteOldClusterTrustBundleTarget deletes the old ClusterTrustBundle target
+// stored in the Bundle status if the Bundle no longer targets it, and it is
+// owned by the Bundle. Changes to the data and metadata of a ClusterTrustBundle
+// which is still targeted are applied when the target is synced.
+func (b *bundle) deleteOldClusterTrustBundleTarget(ctx context.Context, log logr.Logger, bundle *trustapi.Bundle) error {
+	name := clusterTrustBundleTargetName(bundle, bundle.Status.Target)
+	if bundle.Spec.Target.ClusterTrustBundle != nil && clusterTrustBundleTargetName(bundle, &bundle.Spec.Target) == name {
+		return nil
+	}
+
+	clusterTrustBundle := &certificatesv1alpha1.ClusterTrustBundle{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	err := b.targetDirectClient.Get(ctx, client.ObjectKeyFromObject(clusterTrustBundle), clusterTrustBundle)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		log.Error(err, "failed to get target ClusterTrustBundle")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetGetError", "Failed to get target ClusterTrustBundle: %s", err)
+		return fmt.Errorf("failed to get target ClusterTrustBundle: %w", err)
+	}
+
+	if !metav1.IsControlledBy(clusterTrustBundle, bundle) {
+		return nil
+	}
+
+	if err := b.targetDirectClient.Delete(ctx, clusterTrustBundle); err != nil {
+		log.Error(err, "failed to delete old ClusterTrustBundle target")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old ClusterTrustBundle target: %s", err)
+		return fmt.Errorf("failed to delete old ClusterTrustBundle target: %w", err)
+	}
+
+	return nil
 }
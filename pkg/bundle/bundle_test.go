@@ -18,12 +18,15 @@ package bundle
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -91,12 +94,16 @@ func Test_Reconcile(t *testing.T) {
 					{Secret: &trustapi.SourceObjectKeySelector{Name: sourceSecretName, KeySelector: trustapi.KeySelector{Key: sourceSecretKey}}},
 					{InLine: pointer.String(dummy.TestCertificate3)},
 				},
-				Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+				Target: trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 			},
 		}
 
 		baseBundleOwnerRef = []metav1.OwnerReference{*metav1.NewControllerRef(baseBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))}
 
+		hashAnnotation = func(data string) map[string]string {
+			return map[string]string{BundleHashAnnotationKey: targetDataHash(data, nil, nil)}
+		}
+
 		namespaces = []client.Object{
 			&corev1.Namespace{TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: trustNamespace}},
 			&corev1.Namespace{TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}},
@@ -107,23 +114,48 @@ func Test_Reconcile(t *testing.T) {
 		fixedmetatime = &metav1.Time{Time: fixedTime}
 		fixedclock    = fakeclock.NewFakeClock(fixedTime)
 
+		namespaceSelectorWithExclusions = trustapi.NamespaceSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tenant", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"untrusted"}}},
+			ExcludeNames:     []string{"ns-1"},
+		}
+
 		testDefaultPackage = &fspkg.Package{
 			Name:    "testpkg",
 			Version: "123",
 			Bundle:  dummy.TestCertificate5,
 		}
+
+		baseSourcesCertificatesStatus = []trustapi.SourceCertificatesStatus{
+			{Source: "sources[0]", Count: 1, EarliestExpiry: metav1.NewTime(time.Date(2032, time.November, 22, 13, 3, 54, 0, time.UTC))},
+			{Source: "sources[1]", Count: 1, EarliestExpiry: metav1.NewTime(time.Date(2032, time.December, 2, 16, 22, 42, 0, time.UTC))},
+			{Source: "sources[2]", Count: 1, EarliestExpiry: metav1.NewTime(time.Date(2035, time.June, 4, 11, 4, 38, 0, time.UTC))},
+		}
+
+		baseCertificatesStatus = &trustapi.CertificatesStatus{
+			Count:                 3,
+			EarliestExpiry:        metav1.NewTime(time.Date(2032, time.November, 22, 13, 3, 54, 0, time.UTC)),
+			EarliestExpirySubject: "CN=cmct-test-root,O=cert-manager",
+			SHA256:                "2a8d0d508c227d28212f6b598f4d849e14dab50f74ba58cbe380ba184bc6b200",
+			Sources:               baseSourcesCertificatesStatus,
+		}
 	)
 
 	tests := map[string]struct {
-		existingSecrets         []client.Object
-		existingConfigMaps      []client.Object
-		existingNamespaces      []client.Object
-		existingBundles         []client.Object
-		configureDefaultPackage bool
-		expResult               ctrl.Result
-		expError                bool
-		expObjects              []client.Object
-		expEvent                string
+		existingSecrets                 []client.Object
+		existingConfigMaps              []client.Object
+		existingNamespaces              []client.Object
+		existingBundles                 []client.Object
+		existingClusterTrustBundles     []client.Object
+		configureDefaultPackage         bool
+		enableSecretTargets             bool
+		enableClusterTrustBundleTargets bool
+		failNamespaces                  []string
+		expiryWarningWindow             time.Duration
+		expResult                       ctrl.Result
+		expError                        bool
+		expObjects                      []client.Object
+		expNotFound                     []client.Object
+		expEvent                        string
 	}{
 		"if no bundle exists, should return nothing": {
 			existingSecrets:    []client.Object{sourceSecret},
@@ -157,6 +189,62 @@ func Test_Reconcile(t *testing.T) {
 			),
 			expEvent: `Warning SourceNotFound Bundle source was not found: failed to retrieve bundle from source: configmaps "source-configmap" not found`,
 		},
+		"if Bundle certificates violate a crypto policy in Fail mode, update with 'crypto policy violation'": {
+			existingSecrets:    []client.Object{sourceSecret},
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingNamespaces: namespaces,
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleCryptoPolicy(trustapi.CryptoPolicy{AllowedCurves: []string{"P-256"}, Mode: trustapi.CryptoPolicyModeFail}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleCryptoPolicy(trustapi.CryptoPolicy{AllowedCurves: []string{"P-256"}, Mode: trustapi.CryptoPolicyModeFail}),
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{Conditions: []trustapi.BundleCondition{
+						{
+							Type:               trustapi.BundleConditionSynced,
+							Status:             corev1.ConditionFalse,
+							Reason:             "CryptoPolicyViolation",
+							Message:            `Bundle certificates violate the crypto policy: "CN=cmct-test-root,O=cert-manager": curve Ed25519 is not allowed`,
+							ObservedGeneration: bundleGeneration,
+							LastTransitionTime: fixedmetatime,
+						},
+					}}),
+				),
+			),
+			expEvent: `Warning CryptoPolicyViolation Bundle certificates violate the crypto policy: "CN=cmct-test-root,O=cert-manager": curve Ed25519 is not allowed`,
+		},
+		"if Bundle certificates do not meet the CA requirement, update with 'CA requirement violation'": {
+			existingSecrets:    []client.Object{sourceSecret},
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingNamespaces: namespaces,
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleCARequirement(trustapi.CARequirementCA),
+				gen.AppendBundleInLineSource(dummy.TestLeafCertificate),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleCARequirement(trustapi.CARequirementCA),
+					gen.AppendBundleInLineSource(dummy.TestLeafCertificate),
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{Conditions: []trustapi.BundleCondition{
+						{
+							Type:               trustapi.BundleConditionSynced,
+							Status:             corev1.ConditionFalse,
+							Reason:             "CARequirementViolation",
+							Message:            `Bundle certificates do not meet the CA requirement: certificate "CN=cmct-test-leaf,O=cert-manager" is not a CA certificate`,
+							ObservedGeneration: bundleGeneration,
+							LastTransitionTime: fixedmetatime,
+						},
+					}}),
+				),
+			),
+			expEvent: `Warning CARequirementViolation Bundle certificates do not meet the CA requirement: certificate "CN=cmct-test-leaf,O=cert-manager" is not a CA certificate`,
+		},
 		"if Bundle references a ConfigMap whose key doesn't exist, update with 'not found'": {
 			existingSecrets:    []client.Object{sourceSecret},
 			existingNamespaces: namespaces,
@@ -236,19 +324,58 @@ func Test_Reconcile(t *testing.T) {
 			),
 			expEvent: `Warning SourceNotFound Bundle source was not found: failed to retrieve bundle from source: no data found in Secret trust-namespace/source-secret at key "secret-key"`,
 		},
+		"if Bundle references a JKS password Secret which does not exist, update with 'not found'": {
+			existingSecrets:    []client.Object{sourceSecret},
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingNamespaces: namespaces,
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{
+					KeySelector:       trustapi.KeySelector{Key: "target.jks"},
+					PasswordSecretRef: &trustapi.SecretKeySelector{Name: "password-secret", KeySelector: trustapi.KeySelector{Key: "password"}},
+				}}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{
+						KeySelector:       trustapi.KeySelector{Key: "target.jks"},
+						PasswordSecretRef: &trustapi.SecretKeySelector{Name: "password-secret", KeySelector: trustapi.KeySelector{Key: "password"}},
+					}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Conditions: []trustapi.BundleCondition{
+						{
+							Type:               trustapi.BundleConditionSynced,
+							Status:             corev1.ConditionFalse,
+							Reason:             "PasswordNotFound",
+							Message:            `Bundle target password was not found: failed to get JKS password: secrets "password-secret" not found`,
+							ObservedGeneration: bundleGeneration,
+							LastTransitionTime: fixedmetatime,
+						},
+					}}),
+				),
+			),
+			expEvent: `Warning PasswordNotFound Bundle target password was not found: failed to get JKS password: secrets "password-secret" not found`,
+		},
 		"if Bundle Status Target doesn't match the Spec Target, delete old targets and update": {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap, &corev1.ConfigMap{
-				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name}, Data: map[string]string{"A": "B", "old-target": "foo"},
+				TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name,
+					ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:old-target":{}}}`),
+				},
+				Data: map[string]string{"A": "B", "old-target": "foo"},
 			},
 				&corev1.ConfigMap{
-					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name}, Data: map[string]string{"A": "B", "old-target": "foo"},
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name,
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationApply, `{"f:data":{"f:old-target":{}}}`),
+					},
+					Data: map[string]string{"A": "B", "old-target": "foo"},
 				}},
 			existingBundles: []client.Object{
 				gen.BundleFrom(baseBundle,
-					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "old-target"}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "old-target"}}}}),
 				),
 			},
 			existingSecrets: []client.Object{sourceSecret},
@@ -257,15 +384,15 @@ func Test_Reconcile(t *testing.T) {
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
-					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}}}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1001"}, Data: map[string]string{"A": "B"},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
 				},
 			),
 			expEvent: "Normal DeleteOldTarget Deleting old targets as Bundle target has been modified",
@@ -274,20 +401,26 @@ func Test_Reconcile(t *testing.T) {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
-					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name}, Data: map[string]string{"A": "B", "old-target": "foo"}, BinaryData: map[string][]byte{"target.jks": []byte("foo")},
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name,
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:old-target":{}},"f:binaryData":{"f:target.jks":{}}}`),
+					},
+					Data: map[string]string{"A": "B", "old-target": "foo"}, BinaryData: map[string][]byte{"target.jks": []byte("foo")},
 				},
 				&corev1.ConfigMap{
-					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name}, Data: map[string]string{"A": "B", "old-target": "foo"}, BinaryData: map[string][]byte{"target.jks": []byte("foo")},
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name,
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:old-target":{}},"f:binaryData":{"f:target.jks":{}}}`),
+					},
+					Data: map[string]string{"A": "B", "old-target": "foo"}, BinaryData: map[string][]byte{"target.jks": []byte("foo")},
 				}},
 			existingSecrets: []client.Object{sourceSecret},
 			existingBundles: []client.Object{
 				gen.BundleFrom(baseBundle,
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: "old-target"},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}},
+						ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "old-target"}},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}},
 					}}),
 				)},
 			expResult: ctrl.Result{},
@@ -295,42 +428,93 @@ func Test_Reconcile(t *testing.T) {
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: targetKey},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}},
+						ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}},
 					}}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1001"}, Data: map[string]string{"A": "B"}, BinaryData: map[string][]byte{"target.jks": []byte("foo")},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1001"}, Data: map[string]string{"A": "B"}, BinaryData: map[string][]byte{"target.jks": []byte("foo")},
 				},
 			),
 			expEvent: "Normal DeleteOldTarget Deleting old targets as Bundle target has been modified",
 		},
-		"if Bundle Status Target.AdditionalFormats.JKS doesn't match the Spec Target.AdditionalFormats.JKS, delete old targets and update": {
+		"if Bundle Spec Target ConfigMap has been renamed, delete owned old-named targets and update": {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name}, Data: map[string]string{"A": "B", targetKey: "foo"}, BinaryData: map[string][]byte{"old-target.jks": []byte("foo")},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Labels: map[string]string{"team": "a"}}, Data: map[string]string{targetKey: "foo"},
 				},
+				&corev1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Labels: map[string]string{"team": "a", "other": "label"},
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationApply, `{"f:metadata":{"f:labels":{"f:team":{}}},"f:data":{"f:target-key":{}}}`),
+					},
+					Data: map[string]string{"A": "B", targetKey: "foo"},
+				},
+			},
+			existingSecrets: []client.Object{sourceSecret},
+			existingBundles: []client.Object{
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleTarget(trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{Name: "new-name", KeySelector: trustapi.KeySelector{Key: targetKey}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
+						ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+						Metadata:  &trustapi.TargetMetadata{Labels: map[string]string{"team": "a"}},
+					}}),
+				),
+			},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTarget(trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{Name: "new-name", KeySelector: trustapi.KeySelector{Key: targetKey}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
+						ConfigMap: &trustapi.ConfigMapTarget{Name: "new-name", KeySelector: trustapi.KeySelector{Key: targetKey}},
+					}}),
+				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name}, Data: map[string]string{"A": "B", targetKey: "foo"}, BinaryData: map[string][]byte{"old-target.jks": []byte("foo")},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Labels: map[string]string{"other": "label"}, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+				},
+			),
+			expNotFound: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name}},
+			},
+			expEvent: "Normal DeleteOldTarget Deleting old targets as Bundle target has been modified",
+		},
+		"if Bundle Status Target.AdditionalFormats.JKS doesn't match the Spec Target.AdditionalFormats.JKS, delete old targets and update": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap,
+				&corev1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name,
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationApply, `{"f:data":{"f:target-key":{}},"f:binaryData":{"f:old-target.jks":{}}}`),
+					},
+					Data: map[string]string{"A": "B", targetKey: "foo"}, BinaryData: map[string][]byte{"old-target.jks": []byte("foo")},
+				},
+				&corev1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name,
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationApply, `{"f:data":{"f:target-key":{}},"f:binaryData":{"f:old-target.jks":{}}}`),
+					},
+					Data: map[string]string{"A": "B", targetKey: "foo"}, BinaryData: map[string][]byte{"old-target.jks": []byte("foo")},
 				},
 			},
 			existingSecrets: []client.Object{sourceSecret},
 			existingBundles: []client.Object{
 				gen.BundleFrom(baseBundle,
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: targetKey},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "old-target.jks"}},
+						ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "old-target.jks"}}},
 					}}),
 				),
 			},
@@ -339,19 +523,58 @@ func Test_Reconcile(t *testing.T) {
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: targetKey},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}},
+						ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}},
 					}}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"}, Data: map[string]string{"A": "B", targetKey: "foo"},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, ResourceVersion: "1000"}, Data: map[string]string{"A": "B"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"}, Data: map[string]string{"A": "B", targetKey: "foo"},
+				},
+			),
+			expEvent: "Normal DeleteOldTarget Deleting old targets as Bundle target has been modified",
+		},
+		"if Bundle Status Target.Keys doesn't match the Spec Target.Keys, delete keys which are no longer listed and update": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap,
+				&corev1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name,
+						ManagedFields: trustManagerManagedFields(metav1.ManagedFieldsOperationApply, `{"f:data":{"f:target-key":{},"f:ca.crt":{}},"f:binaryData":{"f:trust.der":{}}}`),
+					},
+					Data: map[string]string{"A": "B", targetKey: "foo", "ca.crt": "foo"}, BinaryData: map[string][]byte{"trust.der": []byte("foo")},
+				},
+			},
+			existingSecrets: []client.Object{sourceSecret},
+			existingBundles: []client.Object{
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
+						ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+						Keys: []trustapi.TargetKey{
+							{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+							{KeySelector: trustapi.KeySelector{Key: "trust.der"}, Format: trustapi.TargetFormatDER},
+						},
+					}}),
+				),
+			},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
+						ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+					}}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"}, Data: map[string]string{"A": "B", targetKey: "foo"},
 				},
 			),
 			expEvent: "Normal DeleteOldTarget Deleting old targets as Bundle target has been modified",
@@ -367,7 +590,9 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -382,22 +607,367 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
 			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
 		},
+		"if syncing to a Namespace fails, sync all other Namespaces and report the failure": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingSecrets:    []client.Object{sourceSecret},
+			existingBundles:    []client.Object{gen.BundleFrom(baseBundle)},
+			failNamespaces:     []string{"ns-1"},
+			expResult:          ctrl.Result{Requeue: true},
+			expError:           false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces: &trustapi.NamespaceSyncStatus{
+							Synced: 2,
+							Failed: 1,
+							FailedNamespaces: []trustapi.NamespaceSyncFailure{
+								{Namespace: "ns-1", Message: `injected failure in namespace "ns-1"`},
+							},
+						},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "SyncTargetFailed",
+								Message:            `Failed to sync bundle to 1 namespaces: "ns-1": injected failure in namespace "ns-1"`,
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expNotFound: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name}},
+			},
+			expEvent: `Warning SyncTargetFailed Failed to sync bundle to 1 namespaces: "ns-1": injected failure in namespace "ns-1"`,
+		},
+		"if Bundle contains certificates which expire within the expiry warning window, add condition and requeue once the next certificate enters the window": {
+			existingNamespaces:  namespaces,
+			existingConfigMaps:  []client.Object{sourceConfigMap},
+			existingSecrets:     []client.Object{sourceSecret},
+			existingBundles:     []client.Object{gen.BundleFrom(baseBundle)},
+			expiryWarningWindow: 12 * 365 * 24 * time.Hour,
+			expResult:           ctrl.Result{RequeueAfter: time.Date(2035, time.June, 4, 11, 4, 38, 0, time.UTC).Add(-12 * 365 * 24 * time.Hour).Sub(fixedTime)},
+			expError:            false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionCertificatesExpiringSoon,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "CertificatesExpiringSoon",
+								Message:            `Bundle contains certificates which expire within 105120h0m0s: certificate "CN=cmct-test-root,O=cert-manager" expires at 2032-11-22T13:03:54Z; certificate "CN=cmct-test-root,O=cert-manager" expires at 2032-12-02T16:22:42Z`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expEvent: `Warning CertificatesExpiringSoon Bundle contains certificates which expire within 105120h0m0s: certificate "CN=cmct-test-root,O=cert-manager" expires at 2032-11-22T13:03:54Z; certificate "CN=cmct-test-root,O=cert-manager" expires at 2032-12-02T16:22:42Z`,
+		},
+		"if Bundle no longer contains certificates which expire within the expiry warning window, remove condition": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingSecrets:    []client.Object{sourceSecret},
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleStatus(trustapi.BundleStatus{
+					Conditions: []trustapi.BundleCondition{
+						{
+							Type:               trustapi.BundleConditionCertificatesExpiringSoon,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: fixedmetatime,
+							Reason:             "CertificatesExpiringSoon",
+							Message:            `Bundle contains certificates which expire within 720h0m0s: certificate "CN=old" expires at 2021-01-02T00:00:00Z`,
+							ObservedGeneration: bundleGeneration,
+						},
+					},
+				}),
+			)},
+			expiryWarningWindow: 30 * 24 * time.Hour,
+			expResult:           ctrl.Result{RequeueAfter: time.Date(2032, time.October, 23, 13, 3, 54, 0, time.UTC).Sub(fixedTime)},
+			expError:            false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if Bundle has a Secret target but Secret targets are disabled, update with error": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingSecrets:    []client.Object{sourceSecret},
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleTarget(trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: targetKey}}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTarget(trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: targetKey}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "SecretTargetsDisabled",
+								Message:            "Bundle has a Secret target but Secret targets are not enabled in trust-manager",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+			),
+			expEvent: "Warning SecretTargetsDisabled Bundle has a Secret target but Secret targets are not enabled in trust-manager",
+		},
+		"if Bundle with Secret target not synced everywhere, sync and update Synced": {
+			existingNamespaces:  namespaces,
+			existingConfigMaps:  []client.Object{sourceConfigMap},
+			existingSecrets:     []client.Object{sourceSecret},
+			enableSecretTargets: true,
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleTarget(trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: targetKey}}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTarget(trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: targetKey}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+				&corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string][]byte{targetKey: []byte(dummy.DefaultJoinedCerts())},
+				},
+				&corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string][]byte{targetKey: []byte(dummy.DefaultJoinedCerts())},
+				},
+				&corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string][]byte{targetKey: []byte(dummy.DefaultJoinedCerts())},
+				},
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if Bundle has a ClusterTrustBundle target but ClusterTrustBundle targets are disabled, update with error": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingSecrets:    []client.Object{sourceSecret},
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleTarget(trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{}}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTarget(trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "ClusterTrustBundleTargetsDisabled",
+								Message:            "Bundle has a ClusterTrustBundle target but ClusterTrustBundle targets are not enabled in trust-manager",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+			),
+			expEvent: "Warning ClusterTrustBundleTargetsDisabled Bundle has a ClusterTrustBundle target but ClusterTrustBundle targets are not enabled in trust-manager",
+		},
+		"if Bundle has a ClusterTrustBundle source but ClusterTrustBundle sources are disabled, update with error": {
+			existingNamespaces: namespaces,
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingSecrets:    []client.Object{sourceSecret},
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleSources([]trustapi.BundleSource{{ClusterTrustBundle: &trustapi.ClusterTrustBundleSource{SignerName: "example.com/foo"}}}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleSources([]trustapi.BundleSource{{ClusterTrustBundle: &trustapi.ClusterTrustBundleSource{SignerName: "example.com/foo"}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "ClusterTrustBundleSourcesDisabled",
+								Message:            "Bundle has a ClusterTrustBundle source but ClusterTrustBundle sources are not enabled in trust-manager",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+			),
+			expEvent: "Warning ClusterTrustBundleSourcesDisabled Bundle has a ClusterTrustBundle source but ClusterTrustBundle sources are not enabled in trust-manager",
+		},
+		"if Bundle with only a ClusterTrustBundle target not synced, sync it with the signer name prefix, count no Namespaces and update Synced": {
+			existingNamespaces:              namespaces,
+			existingConfigMaps:              []client.Object{sourceConfigMap},
+			existingSecrets:                 []client.Object{sourceSecret},
+			enableClusterTrustBundleTargets: true,
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleTarget(trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{SignerName: "example.com/foo"}}),
+			)},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTarget(trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{SignerName: "example.com/foo"}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{},
+						Target:       &trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{SignerName: "example.com/foo"}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+				&certificatesv1alpha1.ClusterTrustBundle{
+					TypeMeta:   metav1.TypeMeta{Kind: "ClusterTrustBundle", APIVersion: "certificates.k8s.io/v1alpha1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "example.com:foo:" + baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Spec: certificatesv1alpha1.ClusterTrustBundleSpec{
+						SignerName:  "example.com/foo",
+						TrustBundle: dummy.DefaultJoinedCerts(),
+					},
+				},
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if Bundle no longer targets a ClusterTrustBundle, delete the owned old ClusterTrustBundle and update": {
+			existingNamespaces:              namespaces,
+			existingConfigMaps:              []client.Object{sourceConfigMap},
+			existingSecrets:                 []client.Object{sourceSecret},
+			enableClusterTrustBundleTargets: true,
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleStatus(trustapi.BundleStatus{
+					Target: &trustapi.BundleTarget{
+						ConfigMap:          &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+						ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{},
+					},
+				}),
+			)},
+			existingClusterTrustBundles: []client.Object{
+				&certificatesv1alpha1.ClusterTrustBundle{
+					ObjectMeta: metav1.ObjectMeta{Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{TrustBundle: dummy.DefaultJoinedCerts()},
+				},
+			},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
+					}),
+				),
+			),
+			expNotFound: []client.Object{
+				&certificatesv1alpha1.ClusterTrustBundle{ObjectMeta: metav1.ObjectMeta{Name: baseBundle.Name}},
+			},
+			expEvent: "Normal DeleteOldTarget Deleting old targets as Bundle target has been modified",
+		},
 		"if Bundle not synced everywhere, sync except Namespaces that are terminating and update Synced": {
 			existingNamespaces: append(namespaces,
 				&corev1.Namespace{
@@ -415,7 +985,9 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3, Skipped: 1},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{{
 							Type:               trustapi.BundleConditionSynced,
 							Status:             corev1.ConditionTrue,
@@ -428,17 +1000,17 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -472,8 +1044,10 @@ func Test_Reconcile(t *testing.T) {
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleTargetNamespaceSelectorMatchLabels(map[string]string{"foo": "bar"}),
 					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 2, Skipped: 3},
 						Target: &trustapi.BundleTarget{
-							ConfigMap: &trustapi.KeySelector{Key: targetKey},
+							ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
 							NamespaceSelector: &trustapi.NamespaceSelector{
 								MatchLabels: map[string]string{"foo": "bar"},
 							},
@@ -490,12 +1064,12 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "random-namespace", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "random-namespace", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "another-random-namespace", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "another-random-namespace", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -506,17 +1080,17 @@ func Test_Reconcile(t *testing.T) {
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			},
@@ -530,8 +1104,10 @@ func Test_Reconcile(t *testing.T) {
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleTargetNamespaceSelectorMatchLabels(map[string]string{"foo": "bar"}),
 					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 0, Skipped: 3},
 						Target: &trustapi.BundleTarget{
-							ConfigMap: &trustapi.KeySelector{Key: targetKey},
+							ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
 							NamespaceSelector: &trustapi.NamespaceSelector{
 								MatchLabels: map[string]string{"foo": "bar"},
 							},
@@ -549,6 +1125,57 @@ func Test_Reconcile(t *testing.T) {
 			),
 			expEvent: "Normal Synced Successfully synced Bundle to namespaces with selector [matchLabels:map[foo:bar]]",
 		},
+		"if Bundle not synced everywhere, sync only to Namespaces matching expressions and not excluded by name": {
+			existingNamespaces: append(namespaces,
+				&corev1.Namespace{
+					TypeMeta:   metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Name: "untrusted-namespace", Labels: map[string]string{"tenant": "untrusted"}},
+				},
+			),
+			existingConfigMaps: []client.Object{sourceConfigMap},
+			existingSecrets:    []client.Object{sourceSecret},
+			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
+				gen.SetBundleTargetNamespaceSelector(namespaceSelectorWithExclusions))},
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleTargetNamespaceSelector(namespaceSelectorWithExclusions),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 2, Skipped: 2},
+						Target: &trustapi.BundleTarget{
+							ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
+							NamespaceSelector: &namespaceSelectorWithExclusions,
+						},
+						Conditions: []trustapi.BundleCondition{{
+							Type:               trustapi.BundleConditionSynced,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: &metav1.Time{Time: fixedclock.Now().Local()},
+							Reason:             "Synced",
+							Message:            "Successfully synced Bundle to namespaces with selector [matchExpressions:[tenant NotIn [untrusted]] excludeNames:[ns-1]]",
+							ObservedGeneration: bundleGeneration,
+						}},
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expNotFound: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "untrusted-namespace", Name: baseBundle.Name}},
+			},
+			expEvent: "Normal Synced Successfully synced Bundle to namespaces with selector [matchExpressions:[tenant NotIn [untrusted]] excludeNames:[ns-1]]",
+		},
 		"if Bundle synced but doesn't have owner reference, should sync and update": {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap,
@@ -570,7 +1197,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleStatus(trustapi.BundleStatus{
 						Target: &trustapi.BundleTarget{
-							ConfigMap: &trustapi.KeySelector{Key: targetKey},
+							ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}},
 						},
 						Conditions: []trustapi.BundleCondition{
 							{
@@ -590,7 +1217,9 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -605,17 +1234,17 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -627,17 +1256,17 @@ func Test_Reconcile(t *testing.T) {
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			},
@@ -648,7 +1277,9 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -663,17 +1294,17 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -683,15 +1314,15 @@ func Test_Reconcile(t *testing.T) {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			},
@@ -699,7 +1330,9 @@ func Test_Reconcile(t *testing.T) {
 			existingBundles: []client.Object{
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -720,7 +1353,9 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1000"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -735,17 +1370,17 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -780,15 +1415,15 @@ func Test_Reconcile(t *testing.T) {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts())},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			},
@@ -797,7 +1432,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.AppendBundleUsesDefaultPackage(),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -819,7 +1454,17 @@ func Test_Reconcile(t *testing.T) {
 					gen.SetBundleResourceVersion("1001"),
 					gen.AppendBundleUsesDefaultPackage(),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: &trustapi.CertificatesStatus{
+							Count:                 4,
+							EarliestExpiry:        baseCertificatesStatus.EarliestExpiry,
+							EarliestExpirySubject: baseCertificatesStatus.EarliestExpirySubject,
+							SHA256:                "84248d39bd7ef81e8c2332fd55dd2da4cd96e2f7b8ec656ff33bfb52bbbbee16",
+							Sources: append(baseSourcesCertificatesStatus,
+								trustapi.SourceCertificatesStatus{Source: "sources[3]", Count: 1, EarliestExpiry: metav1.NewTime(time.Date(2036, time.June, 22, 0, 0, 0, 0, time.UTC))},
+							),
+						},
+						Namespaces: &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:     &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -835,17 +1480,17 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 			),
@@ -855,22 +1500,22 @@ func Test_Reconcile(t *testing.T) {
 			existingNamespaces: namespaces,
 			existingConfigMaps: []client.Object{sourceConfigMap,
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5))},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5))},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5))},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 			},
 			existingSecrets: []client.Object{sourceSecret},
 			existingBundles: []client.Object{gen.BundleFrom(baseBundle,
 				gen.SetBundleStatus(trustapi.BundleStatus{
-					Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+					Target: &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 					Conditions: []trustapi.BundleCondition{
 						{
 							Type:               trustapi.BundleConditionSynced,
@@ -891,7 +1536,9 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Certificates: baseCertificatesStatus,
+						Namespaces:   &trustapi.NamespaceSyncStatus{Synced: 3},
+						Target:       &trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: targetKey}}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -907,17 +1554,17 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, Annotations: hashAnnotation(dummy.DefaultJoinedCerts()), ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -933,11 +1580,16 @@ func Test_Reconcile(t *testing.T) {
 				WithObjects(test.existingBundles...).
 				WithObjects(test.existingNamespaces...).
 				WithObjects(test.existingSecrets...).
+				WithObjects(test.existingClusterTrustBundles...).
 				WithStatusSubresource(test.existingNamespaces...).
 				WithStatusSubresource(test.existingBundles...).
+				WithInterceptorFuncs(failNamespaces(test.failNamespaces)).
 				Build()
 
-			fakerecorder := record.NewFakeRecorder(1)
+			// A Reconcile may emit a warning about the Bundle contents
+			// before the event about the sync result. Only the first event
+			// is checked.
+			fakerecorder := record.NewFakeRecorder(2)
 
 			b := &bundle{
 				targetDirectClient: fakeclient,
@@ -945,8 +1597,11 @@ func Test_Reconcile(t *testing.T) {
 				recorder:           fakerecorder,
 				clock:              fixedclock,
 				Options: Options{
-					Log:       klogr.New(),
-					Namespace: trustNamespace,
+					Log:                              klogr.New(),
+					Namespace:                        trustNamespace,
+					SecretTargetsEnabled:             test.enableSecretTargets,
+					ClusterTrustBundleTargetsEnabled: test.enableClusterTrustBundleTargets,
+					ExpiryWarningWindow:              test.expiryWarningWindow,
 				},
 			}
 
@@ -980,6 +1635,8 @@ func Test_Reconcile(t *testing.T) {
 					actual = &corev1.ConfigMap{}
 				case *corev1.Namespace:
 					actual = &corev1.Namespace{}
+				case *certificatesv1alpha1.ClusterTrustBundle:
+					actual = &certificatesv1alpha1.ClusterTrustBundle{}
 				case *trustapi.Bundle:
 					actual = &trustapi.Bundle{}
 				default:
@@ -988,10 +1645,163 @@ func Test_Reconcile(t *testing.T) {
 
 				err := fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(expObj), actual)
 				assert.NoError(t, err)
+				// Managed fields are tracked by the emulated server-side apply,
+				// but are not part of the expected objects.
+				actual.SetManagedFields(nil)
 				if !apiequality.Semantic.DeepEqual(expObj, actual) {
 					t.Errorf("unexpected expected object\nexp=%#+v\ngot=%#+v", expObj, actual)
 				}
 			}
+
+			for _, notFoundObject := range test.expNotFound {
+				err := fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(notFoundObject), notFoundObject)
+				assert.True(t, apierrors.IsNotFound(err), "expected %T %s to not exist, got err=%v", notFoundObject, client.ObjectKeyFromObject(notFoundObject), err)
+			}
+		})
+	}
+}
+
+func Test_Reconcile_removedTargetKeys(t *testing.T) {
+	const (
+		bundleName     = "test-bundle"
+		trustNamespace = "trust-namespace"
+	)
+
+	tests := map[string]struct {
+		target    trustapi.BundleTarget
+		newTarget trustapi.BundleTarget
+		// Keys of the target after the first sync.
+		expKeys []string
+		// Keys of the target after it was synced with the new target.
+		expNewKeys []string
+	}{
+		"if additional formats are removed from a ConfigMap target, expect their keys to be removed": {
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "trust.pem"}},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					JKS:    &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "trust.jks"}},
+					PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "trust.p12"}},
+				},
+			},
+			newTarget: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "trust.pem"}},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "trust.p12"}},
+				},
+			},
+			expKeys:    []string{"trust.jks", "trust.p12", "trust.pem"},
+			expNewKeys: []string{"trust.p12", "trust.pem"},
+		},
+		"if target keys are removed from a ConfigMap target, expect them to be removed": {
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "trust.pem"}},
+				Keys: []trustapi.TargetKey{
+					{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+					{KeySelector: trustapi.KeySelector{Key: "trust.der"}, Format: trustapi.TargetFormatDER},
+				},
+			},
+			newTarget: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "trust.pem"}},
+				Keys: []trustapi.TargetKey{
+					{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+				},
+			},
+			expKeys:    []string{"ca.crt", "trust.der", "trust.pem"},
+			expNewKeys: []string{"ca.crt", "trust.pem"},
+		},
+		"if additional formats and target keys are removed from a Secret target, expect their keys to be removed": {
+			target: trustapi.BundleTarget{
+				Secret: &trustapi.KeySelector{Key: "trust.pem"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "trust.jks"}},
+				},
+				Keys: []trustapi.TargetKey{
+					{KeySelector: trustapi.KeySelector{Key: "trust.p7b"}, Format: trustapi.TargetFormatPKCS7},
+				},
+			},
+			newTarget: trustapi.BundleTarget{
+				Secret: &trustapi.KeySelector{Key: "trust.pem"},
+			},
+			expKeys:    []string{"trust.jks", "trust.p7b", "trust.pem"},
+			expNewKeys: []string{"trust.pem"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testBundle := &trustapi.Bundle{
+				TypeMeta:   metav1.TypeMeta{Kind: "Bundle", APIVersion: "trust.cert-manager.io/v1alpha1"},
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, UID: "123"},
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String(dummy.TestCertificate1)}},
+					Target:  test.target,
+				},
+			}
+			namespace := &corev1.Namespace{
+				TypeMeta:   metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: trustNamespace},
+			}
+
+			fakeclient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithObjects(testBundle, namespace).
+				WithStatusSubresource(testBundle, namespace).
+				WithInterceptorFuncs(serverSideApply).
+				Build()
+
+			b := &bundle{
+				targetDirectClient: fakeclient,
+				sourceLister:       fakeclient,
+				recorder:           &record.FakeRecorder{},
+				clock:              fakeclock.NewFakeClock(time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)),
+				Options: Options{
+					Log:                  klogr.New(),
+					Namespace:            trustNamespace,
+					SecretTargetsEnabled: true,
+				},
+			}
+
+			// reconcile the Bundle until its status reflects the target, so
+			// that changes to the target are applied by server-side apply.
+			reconcile := func() {
+				for i := 0; i < 2; i++ {
+					_, err := b.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: bundleName}})
+					assert.NoError(t, err)
+				}
+			}
+
+			targetKeys := func() []string {
+				var keys []string
+				if test.target.Secret != nil {
+					var secret corev1.Secret
+					assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: trustNamespace, Name: bundleName}, &secret))
+					for key := range secret.Data {
+						keys = append(keys, key)
+					}
+				} else {
+					var configMap corev1.ConfigMap
+					assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: trustNamespace, Name: bundleName}, &configMap))
+					for key := range configMap.Data {
+						keys = append(keys, key)
+					}
+					for key := range configMap.BinaryData {
+						keys = append(keys, key)
+					}
+				}
+
+				sort.Strings(keys)
+				return keys
+			}
+
+			reconcile()
+			assert.Equal(t, test.expKeys, targetKeys())
+
+			assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(testBundle), testBundle))
+			testBundle.Spec.Target = test.newTarget
+			assert.NoError(t, fakeclient.Update(context.TODO(), testBundle))
+
+			reconcile()
+			assert.Equal(t, test.expNewKeys, targetKeys())
 		})
 	}
 }
@@ -0,0 +1,267 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// certificate is a single certificate of a bundle.
+type certificate struct {
+	// pem is the PEM-encoded certificate, including a trailing newline.
+	pem string
+
+	cert *x509.Certificate
+
+	// fingerprint is the hex-encoded SHA-256 fingerprint of the certificate.
+	fingerprint string
+}
+
+// processBundle turns the concatenated PEM-encoded data of all sources of a
+// Bundle into the data which is synced to its targets. Duplicate certificates
+// are removed, the crypto policy and filter of the Bundle are applied, the
+// remaining certificates are checked against the CA requirement of the Bundle
+// and then ordered according to the Bundle spec.
+func (b *bundle) processBundle(spec trustapi.BundleSpec, data string, resolvedBundle *bundleData) (string, error) {
+	certificates, err := parseCertificates(data)
+	if err != nil {
+		return "", err
+	}
+
+	certificates = deduplicateCertificates(certificates)
+
+	if spec.CryptoPolicy != nil {
+		certificates, err = applyCryptoPolicy(spec.CryptoPolicy, certificates, resolvedBundle)
+		if err != nil {
+			return "", err
+		}
+
+		if len(certificates) == 0 {
+			return "", errors.New("all certificates in bundle were removed by the bundle crypto policy")
+		}
+	}
+
+	if spec.Filter != nil {
+		certificates, err = b.filterCertificates(spec.Filter, certificates, resolvedBundle)
+		if err != nil {
+			return "", err
+		}
+
+		if len(certificates) == 0 {
+			return "", errors.New("all certificates in bundle were removed by the bundle filter")
+		}
+	}
+
+	if err := checkCARequirement(util.EffectiveCARequirement(&spec), certificates); err != nil {
+		return "", err
+	}
+
+	orderCertificates(certificates, spec.Ordering)
+
+	var builder strings.Builder
+	for _, certificate := range certificates {
+		builder.WriteString(certificate.pem)
+	}
+
+	resolvedBundle.certificates = certificatesStatus(certificates, builder.String())
+	resolvedBundle.parsedCertificates = certificates
+
+	return builder.String(), nil
+}
+
+// expiringCertificates returns a description of each certificate of the given
+// resolved Bundle which has expired or expires within the expiry warning
+// window. The Bundle is resolved again once the next certificate enters the
+// window or expires, so that the descriptions are kept up to date.
+func (b *bundle) expiringCertificates(resolvedBundle *bundleData) []string {
+	if b.ExpiryWarningWindow <= 0 {
+		return nil
+	}
+
+	now := b.clock.Now()
+
+	var expiring []string
+	for _, certificate := range resolvedBundle.parsedCertificates {
+		notAfter := certificate.cert.NotAfter
+
+		switch {
+		case now.After(notAfter):
+			expiring = append(expiring, fmt.Sprintf("certificate %q expired at %s", certificate.cert.Subject.String(), notAfter.UTC().Format(time.RFC3339)))
+
+		case now.Add(b.ExpiryWarningWindow).After(notAfter):
+			expiring = append(expiring, fmt.Sprintf("certificate %q expires at %s", certificate.cert.Subject.String(), notAfter.UTC().Format(time.RFC3339)))
+			resolvedBundle.refreshAt(notAfter.Add(time.Second))
+
+		default:
+			resolvedBundle.refreshAt(notAfter.Add(-b.ExpiryWarningWindow))
+		}
+	}
+
+	return expiring
+}
+
+// certificatesStatus summarises the given certificates, which make up the
+// given PEM-encoded bundle.
+func certificatesStatus(certificates []certificate, data string) *trustapi.CertificatesStatus {
+	hash := sha256.Sum256([]byte(data))
+
+	status := &trustapi.CertificatesStatus{
+		Count:  int32(len(certificates)),
+		SHA256: hex.EncodeToString(hash[:]),
+	}
+
+	if earliest := earliestExpiringCertificate(certificates); earliest != nil {
+		status.EarliestExpiry = metav1.NewTime(earliest.cert.NotAfter)
+		status.EarliestExpirySubject = earliest.cert.Subject.String()
+	}
+
+	return status
+}
+
+// sourceCertificatesStatus summarises the given certificates in the data of
+// the given source.
+func sourceCertificatesStatus(source string, certificates []certificate) trustapi.SourceCertificatesStatus {
+	status := trustapi.SourceCertificatesStatus{
+		Source: source,
+		Count:  int32(len(certificates)),
+	}
+
+	if earliest := earliestExpiringCertificate(certificates); earliest != nil {
+		status.EarliestExpiry = metav1.NewTime(earliest.cert.NotAfter)
+	}
+
+	return status
+}
+
+// earliestExpiringCertificate returns the first of the given certificates to
+// expire, or nil if there are no certificates.
+func earliestExpiringCertificate(certificates []certificate) *certificate {
+	var earliest *certificate
+	for i := range certificates {
+		if earliest == nil || certificates[i].cert.NotAfter.Before(earliest.cert.NotAfter) {
+			earliest = &certificates[i]
+		}
+	}
+
+	return earliest
+}
+
+// checkCARequirement returns a caRequirementError if any of the given
+// certificates doesn't meet the given CA requirement.
+func checkCARequirement(requirement trustapi.CARequirement, certificates []certificate) error {
+	if len(requirement) == 0 || requirement == trustapi.CARequirementAny {
+		return nil
+	}
+
+	var violations []string
+	for _, certificate := range certificates {
+		if err := util.ValidateCACertificate(certificate.cert, requirement == trustapi.CARequirementSelfSignedCA); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	if len(violations) > 0 {
+		return caRequirementError{errors.New(strings.Join(violations, "; "))}
+	}
+
+	return nil
+}
+
+// parseCertificates parses all certificates in the given PEM-encoded bundle.
+func parseCertificates(data string) ([]certificate, error) {
+	certificatesPEM, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PEM data in bundle: %w", err)
+	}
+
+	certificates := make([]certificate, 0, len(certificatesPEM))
+	for _, certPEM := range certificatesPEM {
+		block, _ := pem.Decode(certPEM)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in bundle: %w", err)
+		}
+
+		certificates = append(certificates, certificate{
+			pem:         string(certPEM),
+			cert:        cert,
+			fingerprint: certificateFingerprint(cert),
+		})
+	}
+
+	return certificates, nil
+}
+
+// deduplicateCertificates removes all but the first occurrence of any
+// certificate with the same DER encoding.
+func deduplicateCertificates(certificates []certificate) []certificate {
+	seen := make(map[string]bool, len(certificates))
+
+	deduplicated := certificates[:0]
+	for _, certificate := range certificates {
+		if seen[certificate.fingerprint] {
+			continue
+		}
+
+		seen[certificate.fingerprint] = true
+		deduplicated = append(deduplicated, certificate)
+	}
+
+	return deduplicated
+}
+
+// orderCertificates sorts the given certificates in place in the given order.
+// Certificates are left in source order if no order is given.
+func orderCertificates(certificates []certificate, ordering trustapi.BundleOrdering) {
+	switch ordering {
+	case trustapi.BundleOrderingSubject:
+		sort.SliceStable(certificates, func(i, j int) bool {
+			iSubject, jSubject := certificates[i].cert.Subject.String(), certificates[j].cert.Subject.String()
+			if iSubject != jSubject {
+				return iSubject < jSubject
+			}
+
+			return certificates[i].fingerprint < certificates[j].fingerprint
+		})
+
+	case trustapi.BundleOrderingFingerprint:
+		sort.SliceStable(certificates, func(i, j int) bool {
+			return certificates[i].fingerprint < certificates[j].fingerprint
+		})
+	}
+}
+
+// certificateFingerprint returns the hex-encoded SHA-256 fingerprint of the
+// given certificate.
+func certificateFingerprint(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(hash[:])
+}
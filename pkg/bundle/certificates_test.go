@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_processBundle(t *testing.T) {
+	fingerprint := func(t *testing.T, certPEM string) string {
+		certificates, err := parseCertificates(certPEM)
+		if err != nil || len(certificates) != 1 {
+			t.Fatalf("failed to parse dummy certificate: %v", err)
+		}
+
+		return certificates[0].fingerprint
+	}
+
+	// TestCertificate1 and TestCertificate2 share the same subject, so are
+	// ordered by fingerprint when ordering by subject.
+	sameSubjectByFingerprint := func(t *testing.T) []string {
+		certs := []string{dummy.TestCertificate1, dummy.TestCertificate2}
+		sort.Slice(certs, func(i, j int) bool {
+			return fingerprint(t, certs[i]) < fingerprint(t, certs[j])
+		})
+
+		return certs
+	}
+
+	tests := map[string]struct {
+		spec trustapi.BundleSpec
+		data string
+
+		expData  func(t *testing.T) string
+		expError bool
+	}{
+		"if no ordering is given, keep certificates in source order": {
+			data: dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate5),
+			expData: func(t *testing.T) string {
+				return dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate5)
+			},
+		},
+		"if certificates are duplicated, keep the first occurrence only": {
+			data: dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate1),
+			expData: func(t *testing.T) string {
+				return dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1)
+			},
+		},
+		"if ordering by subject, order by subject and then by fingerprint": {
+			spec: trustapi.BundleSpec{Ordering: trustapi.BundleOrderingSubject},
+			data: dummy.JoinCerts(dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate5),
+			expData: func(t *testing.T) string {
+				return dummy.JoinCerts(append([]string{dummy.TestCertificate5, dummy.TestCertificate3}, sameSubjectByFingerprint(t)...)...)
+			},
+		},
+		"if ordering by fingerprint, order by fingerprint": {
+			spec: trustapi.BundleSpec{Ordering: trustapi.BundleOrderingFingerprint},
+			data: dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate5),
+			expData: func(t *testing.T) string {
+				certs := []string{dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate5}
+				sort.Slice(certs, func(i, j int) bool {
+					return fingerprint(t, certs[i]) < fingerprint(t, certs[j])
+				})
+
+				return dummy.JoinCerts(certs...)
+			},
+		},
+		"if all certificates meet the CA requirement, keep all certificates": {
+			spec: trustapi.BundleSpec{CARequirement: trustapi.CARequirementSelfSignedCA},
+			data: dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1),
+			expData: func(t *testing.T) string {
+				return dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1)
+			},
+		},
+		"if a leaf certificate is given and CA certificates are required, return an error": {
+			spec:     trustapi.BundleSpec{CARequirement: trustapi.CARequirementCA},
+			data:     dummy.JoinCerts(dummy.TestCertificate3, dummy.TestLeafCertificate),
+			expError: true,
+		},
+		"if a leaf certificate is given and the Bundle has a ClusterTrustBundle target, return an error": {
+			spec: trustapi.BundleSpec{
+				Target:        trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{}},
+				CARequirement: trustapi.CARequirementAny,
+			},
+			data:     dummy.JoinCerts(dummy.TestCertificate3, dummy.TestLeafCertificate),
+			expError: true,
+		},
+		"if an intermediate certificate is given and CA certificates are required, keep all certificates": {
+			spec: trustapi.BundleSpec{CARequirement: trustapi.CARequirementCA},
+			data: dummy.JoinCerts(dummy.TestCertificate3, dummy.TestIntermediateCertificate),
+			expData: func(t *testing.T) string {
+				return dummy.JoinCerts(dummy.TestCertificate3, dummy.TestIntermediateCertificate)
+			},
+		},
+		"if an intermediate certificate is given and self-signed CA certificates are required, return an error": {
+			spec:     trustapi.BundleSpec{CARequirement: trustapi.CARequirementSelfSignedCA},
+			data:     dummy.JoinCerts(dummy.TestCertificate3, dummy.TestIntermediateCertificate),
+			expError: true,
+		},
+		"if all certificates are removed by the filter, return an error": {
+			spec: trustapi.BundleSpec{Filter: &trustapi.BundleFilter{EnforceValidityPeriod: pointer.Bool(true)}},
+			// TestCertificate1 and TestCertificate2 have both expired at the
+			// time used in this test.
+			data:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{clock: fakeclock.NewFakeClock(time.Date(2033, 01, 01, 0, 0, 0, 0, time.UTC))}
+
+			var resolvedBundle bundleData
+			got, err := b.processBundle(test.spec, test.data, &resolvedBundle)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			if test.expError {
+				return
+			}
+
+			assert.Equal(t, test.expData(t), got)
+		})
+	}
+}
+
+func Test_processBundleIsIndependentOfSourceOrder(t *testing.T) {
+	b := &bundle{}
+
+	for _, ordering := range []trustapi.BundleOrdering{trustapi.BundleOrderingSubject, trustapi.BundleOrderingFingerprint} {
+		t.Run(string(ordering), func(t *testing.T) {
+			spec := trustapi.BundleSpec{Ordering: ordering}
+
+			first, err := b.processBundle(spec, dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3), &bundleData{})
+			assert.NoError(t, err)
+
+			second, err := b.processBundle(spec, dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate2, dummy.TestCertificate1, dummy.TestCertificate2), &bundleData{})
+			assert.NoError(t, err)
+
+			assert.Equal(t, first, second)
+		})
+	}
+}
+
+func Test_processBundleCertificatesStatus(t *testing.T) {
+	b := &bundle{}
+
+	var resolvedBundle bundleData
+	data, err := b.processBundle(trustapi.BundleSpec{}, dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate3), &resolvedBundle)
+	assert.NoError(t, err)
+
+	hash := sha256.Sum256([]byte(data))
+
+	assert.Equal(t, &trustapi.CertificatesStatus{
+		Count:                 2,
+		EarliestExpiry:        metav1.NewTime(time.Date(2032, time.November, 22, 13, 3, 54, 0, time.UTC)),
+		EarliestExpirySubject: "CN=cmct-test-root,O=cert-manager",
+		SHA256:                hex.EncodeToString(hash[:]),
+	}, resolvedBundle.certificates)
+}
+
+func Test_expiringCertificates(t *testing.T) {
+	// TestCertificate1 expires at 2032-11-22T13:03:54Z, TestCertificate3 at
+	// 2035-06-04T11:04:38Z.
+	certificates, err := parseCertificates(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3))
+	if err != nil {
+		t.Fatalf("failed to parse dummy certificates: %v", err)
+	}
+
+	tests := map[string]struct {
+		now    time.Time
+		window time.Duration
+
+		expExpiring    []string
+		expNextRefresh time.Time
+	}{
+		"if the expiry warning window is disabled, return nothing": {
+			now:    time.Date(2032, time.November, 1, 0, 0, 0, 0, time.UTC),
+			window: 0,
+		},
+		"if no certificate expires within the window, refresh once the first certificate enters the window": {
+			now:            time.Date(2032, time.January, 1, 0, 0, 0, 0, time.UTC),
+			window:         30 * 24 * time.Hour,
+			expNextRefresh: time.Date(2032, time.October, 23, 13, 3, 54, 0, time.UTC),
+		},
+		"if a certificate expires within the window, return it and refresh once it expires": {
+			now:    time.Date(2032, time.November, 1, 0, 0, 0, 0, time.UTC),
+			window: 30 * 24 * time.Hour,
+			expExpiring: []string{
+				`certificate "CN=cmct-test-root,O=cert-manager" expires at 2032-11-22T13:03:54Z`,
+			},
+			expNextRefresh: time.Date(2032, time.November, 22, 13, 3, 55, 0, time.UTC),
+		},
+		"if a certificate has expired, return it and refresh once the next certificate enters the window": {
+			now:    time.Date(2033, time.January, 1, 0, 0, 0, 0, time.UTC),
+			window: 30 * 24 * time.Hour,
+			expExpiring: []string{
+				`certificate "CN=cmct-test-root,O=cert-manager" expired at 2032-11-22T13:03:54Z`,
+			},
+			expNextRefresh: time.Date(2035, time.May, 5, 11, 4, 38, 0, time.UTC),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{
+				clock:   fakeclock.NewFakeClock(test.now),
+				Options: Options{ExpiryWarningWindow: test.window},
+			}
+
+			resolvedBundle := bundleData{parsedCertificates: certificates}
+			assert.Equal(t, test.expExpiring, b.expiringCertificates(&resolvedBundle))
+			assert.Equal(t, test.expNextRefresh, resolvedBundle.nextRefresh)
+		})
+	}
+}
@@ -21,7 +21,10 @@ import (
 	"fmt"
 	"os"
 
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -78,6 +81,7 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		targetDirectClient: targetDirectClient,
 		sourceLister:       sourceCache,
 		recorder:           mgr.GetEventRecorderFor("bundles"),
+		httpClient:         newURLClient(nil),
 		clock:              clock.RealClock{},
 		Options:            opts,
 	}
@@ -93,6 +97,11 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		b.Options.Log.Info("successfully loaded default package from filesystem", "path", b.Options.DefaultPackageLocation)
 	}
 
+	b.metrics, err = registerMetricsCollector(newMetricsCollector(b.defaultPackage))
+	if err != nil {
+		return err
+	}
+
 	// This informer setup allow us to use the informers from the auxiliary,
 	// namespace-scoped cache to trigger event handlers of the bundle
 	// controller.
@@ -123,18 +132,99 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 	}
 
 	// Only reconcile config maps that match the well known name
-	if err := ctrl.NewControllerManagedBy(mgr).
+	controller := ctrl.NewControllerManagedBy(mgr).
 		Named("bundles").
 
 		////// Targets //////
 
 		// Reconcile a Bundle on events against a ConfigMap that it
 		// owns. Only cache ConfigMap metadata.
-		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &trustapi.Bundle{}, handler.OnlyControllerOwner())).
+		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &trustapi.Bundle{}, handler.OnlyControllerOwner()))
+
+	// Reconcile a Bundle on events against a Secret that it owns. Only cache
+	// Secret metadata. Secrets are only watched in all Namespaces if Secret
+	// targets are enabled, since this requires additional RBAC.
+	if opts.SecretTargetsEnabled {
+		controller = controller.WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &trustapi.Bundle{}, handler.OnlyControllerOwner()))
+	}
+
+	// Reconcile a Bundle on events against a ClusterTrustBundle that it owns.
+	// ClusterTrustBundles are only watched if ClusterTrustBundle targets are
+	// enabled, since the API may not be served by the cluster.
+	if opts.ClusterTrustBundleTargetsEnabled {
+		controller = controller.WatchesMetadata(&certificatesv1alpha1.ClusterTrustBundle{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &trustapi.Bundle{}, handler.OnlyControllerOwner()))
+	}
+
+	// Watch ClusterTrustBundles alongside the other sources in the source
+	// cache. Reconcile Bundles who reference a modified source
+	// ClusterTrustBundle. ClusterTrustBundles are only watched if
+	// ClusterTrustBundle sources are enabled, since the API may not be served
+	// by the cluster.
+	if opts.ClusterTrustBundleSourcesEnabled {
+		clusterTrustBundleInformer, err := sourceCache.GetInformer(ctx, &certificatesv1alpha1.ClusterTrustBundle{})
+		if err != nil {
+			return fmt.Errorf("error creating ClusterTrustBundles informer from source cache: %w", err)
+		}
+
+		controller = controller.WatchesRawSource(&source.Informer{Informer: clusterTrustBundleInformer}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, obj client.Object) []reconcile.Request {
+				// If an error happens here and we do nothing, we run the risk of
+				// having trust Bundles out of sync with this source
+				// ClusterTrustBundle.
+				// Exiting error is the safest option, as it will force a resync on
+				// all Bundles on start.
+				bundleList := b.mustBundleList(ctx)
+
+				clusterTrustBundle, ok := obj.(*certificatesv1alpha1.ClusterTrustBundle)
+				if !ok {
+					return nil
+				}
+
+				var requests []reconcile.Request
+				for _, bundle := range bundleList.Items {
+					for _, source := range bundle.Spec.Sources {
+						if source.ClusterTrustBundle == nil {
+							continue
+						}
+
+						// Bundle references this ClusterTrustBundle as a source,
+						// either by name or by signer. Add to request.
+						if clusterTrustBundleSourceMatches(source.ClusterTrustBundle, clusterTrustBundle) {
+							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+							break
+						}
+					}
+				}
+
+				return requests
+			},
+		))
+	}
+
+	if err := controller.
 
 		// Reconcile trust.cert-manager.io Bundles
 		WatchesRawSource(&source.Informer{Informer: bundleInformer}, &handler.EnqueueRequestForObject{}).
 
+		// Reconcile Bundles who reference a modified Bundle as a source,
+		// either directly or through other Bundles.
+		WatchesRawSource(&source.Informer{Informer: bundleInformer}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, obj client.Object) []reconcile.Request {
+				// If an error happens here and we do nothing, we run the risk of
+				// having trust Bundles out of sync with this source Bundle.
+				// Exiting error is the safest option, as it will force a resync on
+				// all Bundles on start.
+				bundleList := b.mustBundleList(ctx)
+
+				var requests []reconcile.Request
+				for _, name := range dependentBundles(bundleList, obj.GetName()) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+				}
+
+				return requests
+			},
+		)).
+
 		// Watch all Namespaces. Cache whole Namespaces to include Phase Status.
 		// Reconcile all Bundles on a Namespace change.
 		WatchesRawSource(&source.Informer{Informer: namespaceInformer}, handler.EnqueueRequestsFromMapFunc(
@@ -172,8 +262,9 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 							continue
 						}
 
-						// Bundle references this ConfigMap as a source. Add to request.
-						if source.ConfigMap.Name == obj.GetName() {
+						// Bundle references this ConfigMap as a source, either by
+						// name or by label selector. Add to request.
+						if sourceObjectMatches(source.ConfigMap, obj) {
 							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
 							break
 						}
@@ -185,7 +276,7 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		)).
 
 		// Watch Secrets in trust Namespace. Only cache metadata.
-		// Reconcile Bundles who reference a modified source Secret.
+		// Reconcile Bundles who reference a modified source or password Secret.
 		WatchesRawSource(&source.Informer{Informer: secretInformer}, handler.EnqueueRequestsFromMapFunc(
 			func(ctx context.Context, obj client.Object) []reconcile.Request {
 				// If an error happens here and we do nothing, we run the risk of
@@ -196,13 +287,21 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 
 				var requests []reconcile.Request
 				for _, bundle := range bundleList.Items {
+					// Bundle references this Secret for a trust store password.
+					// Add to request, so that targets are encoded again.
+					if referencesPasswordSecret(&bundle, obj.GetName()) {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+						continue
+					}
+
 					for _, source := range bundle.Spec.Sources {
 						if source.Secret == nil {
 							continue
 						}
 
-						// Bundle references this Secret as a source. Add to request.
-						if source.Secret.Name == obj.GetName() {
+						// Bundle references this Secret as a source, either by
+						// name or by label selector. Add to request.
+						if sourceObjectMatches(source.Secret, obj) {
 							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
 							break
 						}
@@ -232,3 +331,93 @@ func (b *bundle) mustBundleList(ctx context.Context) *trustapi.BundleList {
 
 	return &bundleList
 }
+
+// sourceObjectMatches returns true if the given source reference selects the
+// given object, either by name or by label selector.
+func sourceObjectMatches(ref *trustapi.SourceObjectKeySelector, obj client.Object) bool {
+	if len(ref.Name) > 0 {
+		return ref.Name == obj.GetName()
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// clusterTrustBundleSourceMatches returns true if the given source reference
+// selects the given ClusterTrustBundle, either by name or by signer and label
+// selector.
+func clusterTrustBundleSourceMatches(ref *trustapi.ClusterTrustBundleSource, clusterTrustBundle *certificatesv1alpha1.ClusterTrustBundle) bool {
+	if len(ref.Name) > 0 {
+		return ref.Name == clusterTrustBundle.Name
+	}
+
+	if ref.SignerName != clusterTrustBundle.Spec.SignerName {
+		return false
+	}
+
+	selector, err := clusterTrustBundleSourceSelector(ref)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(clusterTrustBundle.Labels))
+}
+
+// referencesPasswordSecret returns true if the given Bundle reads the password
+// of any of its binary trust stores from the named Secret.
+func referencesPasswordSecret(bundle *trustapi.Bundle, name string) bool {
+	for _, key := range bundle.Spec.Target.Keys {
+		if key.PasswordSecretRef != nil && key.PasswordSecretRef.Name == name {
+			return true
+		}
+	}
+
+	formats := bundle.Spec.Target.AdditionalFormats
+	if formats == nil {
+		return false
+	}
+
+	if formats.JKS != nil && formats.JKS.PasswordSecretRef != nil && formats.JKS.PasswordSecretRef.Name == name {
+		return true
+	}
+
+	return formats.PKCS12 != nil && formats.PKCS12.PasswordSecretRef != nil && formats.PKCS12.PasswordSecretRef.Name == name
+}
+
+// dependentBundles returns the names of all Bundles which reference the named
+// Bundle as a source, either directly or transitively through other Bundles.
+// The named Bundle itself is never returned, even if it is part of a cycle.
+func dependentBundles(bundleList *trustapi.BundleList, name string) []string {
+	dependents := make(map[string][]string)
+	for _, bundle := range bundleList.Items {
+		for _, source := range bundle.Spec.Sources {
+			if source.Bundle != nil {
+				dependents[source.Bundle.Name] = append(dependents[source.Bundle.Name], bundle.Name)
+			}
+		}
+	}
+
+	var names []string
+	seen := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dependent := range dependents[current] {
+			if seen[dependent] {
+				continue
+			}
+
+			seen[dependent] = true
+			names = append(names, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_dependentBundles(t *testing.T) {
+	bundleWithSources := func(name string, sources ...string) trustapi.Bundle {
+		bundle := trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, source := range sources {
+			bundle.Spec.Sources = append(bundle.Spec.Sources, trustapi.BundleSource{
+				Bundle: &trustapi.BundleReference{Name: source},
+			})
+		}
+		return bundle
+	}
+
+	tests := map[string]struct {
+		bundles  []trustapi.Bundle
+		name     string
+		expNames []string
+	}{
+		"no Bundles reference the Bundle": {
+			bundles:  []trustapi.Bundle{bundleWithSources("a"), bundleWithSources("b", "c")},
+			name:     "a",
+			expNames: nil,
+		},
+		"Bundles which reference the Bundle directly and transitively should be returned": {
+			bundles: []trustapi.Bundle{
+				bundleWithSources("a"),
+				bundleWithSources("b", "a"),
+				bundleWithSources("c", "b"),
+				bundleWithSources("d", "c", "a"),
+				bundleWithSources("e", "f"),
+			},
+			name:     "a",
+			expNames: []string{"b", "d", "c"},
+		},
+		"Bundles in a cycle should be returned once and not include the Bundle itself": {
+			bundles: []trustapi.Bundle{
+				bundleWithSources("a", "b"),
+				bundleWithSources("b", "a"),
+			},
+			name:     "a",
+			expNames: []string{"b"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := dependentBundles(&trustapi.BundleList{Items: test.bundles}, test.name)
+			assert.Equal(t, test.expNames, got)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// removedReasonExpired is the reason given for certificates which were
+	// removed because their NotAfter time has passed.
+	removedReasonExpired = "Expired"
+
+	// removedReasonNotYetValid is the reason given for certificates which were
+	// removed because their NotBefore time has not yet been reached.
+	removedReasonNotYetValid = "NotYetValid"
+
+	// removedReasonExcluded is the reason given for certificates which were
+	// removed because they match an exclude rule.
+	removedReasonExcluded = "Excluded"
+
+	// removedReasonNotIncluded is the reason given for certificates which were
+	// removed because they match none of the include rules.
+	removedReasonNotIncluded = "NotIncluded"
+)
+
+// filterCertificates returns the certificates which pass the given filter.
+// The removed certificates and the number of certificates removed by each
+// include and exclude rule are recorded in resolvedBundle, together with the
+// next time at which the result of the filter changes.
+func (b *bundle) filterCertificates(filter *trustapi.BundleFilter, certificates []certificate, resolvedBundle *bundleData) ([]certificate, error) {
+	exclude, err := compileMatchers(filter.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude rule: %w", err)
+	}
+
+	include, err := compileMatchers(filter.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include rule: %w", err)
+	}
+
+	enforceValidityPeriod := filter.EnforceValidityPeriod != nil && *filter.EnforceValidityPeriod
+
+	now := b.clock.Now()
+
+	excludeRemoved := make([]int32, len(exclude))
+	var includeRemoved int32
+
+	var kept []certificate
+	for _, certificate := range certificates {
+		var reason string
+		if i := firstMatch(exclude, certificate); i >= 0 {
+			excludeRemoved[i]++
+			reason = removedReasonExcluded
+		} else if len(include) > 0 && firstMatch(include, certificate) < 0 {
+			includeRemoved++
+			reason = removedReasonNotIncluded
+		} else if enforceValidityPeriod {
+			reason = validityPeriodReason(certificate.cert, now, resolvedBundle)
+		}
+
+		if len(reason) > 0 {
+			resolvedBundle.removedCertificates = append(resolvedBundle.removedCertificates, trustapi.RemovedCertificate{
+				Subject: certificate.cert.Subject.String(),
+				SHA256:  certificate.fingerprint,
+				Reason:  reason,
+			})
+
+			continue
+		}
+
+		kept = append(kept, certificate)
+	}
+
+	for i, removed := range excludeRemoved {
+		resolvedBundle.filterRules = append(resolvedBundle.filterRules, trustapi.FilterRuleStatus{
+			Rule:    fmt.Sprintf("exclude[%d]", i),
+			Removed: removed,
+		})
+	}
+
+	if len(include) > 0 {
+		resolvedBundle.filterRules = append(resolvedBundle.filterRules, trustapi.FilterRuleStatus{
+			Rule:    "include",
+			Removed: includeRemoved,
+		})
+	}
+
+	return kept, nil
+}
+
+// certificateMatcher is a compiled trustapi.CertificateMatcher.
+type certificateMatcher struct {
+	sha256       string
+	subject      string
+	subjectRegex *regexp.Regexp
+}
+
+// compileMatchers compiles the given certificate matchers.
+func compileMatchers(matchers []trustapi.CertificateMatcher) ([]certificateMatcher, error) {
+	compiled := make([]certificateMatcher, 0, len(matchers))
+	for i, matcher := range matchers {
+		c := certificateMatcher{
+			sha256:  strings.ToLower(matcher.SHA256),
+			subject: matcher.Subject,
+		}
+
+		if len(matcher.SubjectRegex) > 0 {
+			regex, err := regexp.Compile(matcher.SubjectRegex)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: invalid subject regex: %w", i, err)
+			}
+
+			c.subjectRegex = regex
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return compiled, nil
+}
+
+// matches returns true if the given certificate matches.
+func (m certificateMatcher) matches(certificate certificate) bool {
+	switch {
+	case len(m.sha256) > 0:
+		return m.sha256 == certificate.fingerprint
+
+	case len(m.subject) > 0:
+		return m.subject == certificate.cert.Subject.String()
+
+	case m.subjectRegex != nil:
+		return m.subjectRegex.MatchString(certificate.cert.Subject.String())
+
+	default:
+		return false
+	}
+}
+
+// firstMatch returns the index of the first matcher which matches the given
+// certificate, or -1 if none matches.
+func firstMatch(matchers []certificateMatcher, certificate certificate) int {
+	for i, matcher := range matchers {
+		if matcher.matches(certificate) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// validityPeriodReason returns the reason for removing the given certificate
+// if it is not valid at the given time, or an empty string if it is. The next
+// time at which the validity of the certificate changes is recorded in
+// resolvedBundle.
+func validityPeriodReason(cert *x509.Certificate, now time.Time, resolvedBundle *bundleData) string {
+	switch {
+	case now.Before(cert.NotBefore):
+		resolvedBundle.refreshAt(cert.NotBefore)
+		return removedReasonNotYetValid
+
+	case now.After(cert.NotAfter):
+		return removedReasonExpired
+
+	default:
+		// A certificate is still valid at its NotAfter time, and expires
+		// immediately after it.
+		resolvedBundle.refreshAt(cert.NotAfter.Add(time.Second))
+		return ""
+	}
+}
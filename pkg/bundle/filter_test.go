@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_filterCertificates(t *testing.T) {
+	parse := func(t *testing.T, certPEM string) *x509.Certificate {
+		block, _ := pem.Decode([]byte(certPEM))
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("failed to parse dummy certificate: %s", err)
+		}
+
+		return cert
+	}
+
+	fingerprint := func(certPEM string) string {
+		return certificateFingerprint(parse(t, certPEM))
+	}
+
+	removed := func(t *testing.T, certPEM, reason string) trustapi.RemovedCertificate {
+		cert := parse(t, certPEM)
+		return trustapi.RemovedCertificate{
+			Subject: cert.Subject.String(),
+			SHA256:  certificateFingerprint(cert),
+			Reason:  reason,
+		}
+	}
+
+	var (
+		// TestCertificate2 is not yet valid at beforeCertificate2.
+		beforeCertificate2 = time.Date(2022, 12, 01, 0, 0, 0, 0, time.UTC)
+		// TestCertificate1 and TestCertificate2 have expired at afterCertificate2.
+		afterCertificate2 = time.Date(2033, 01, 01, 0, 0, 0, 0, time.UTC)
+
+		data = dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+	)
+
+	tests := map[string]struct {
+		filter *trustapi.BundleFilter
+		data   string
+		now    time.Time
+
+		expData        string
+		expRemoved     func(t *testing.T) []trustapi.RemovedCertificate
+		expNextRefresh func(t *testing.T) time.Time
+		expFilterRules []trustapi.FilterRuleStatus
+		expError       bool
+	}{
+		"if the validity period is not enforced, keep all certificates": {
+			filter:  &trustapi.BundleFilter{},
+			data:    data,
+			now:     afterCertificate2,
+			expData: data,
+		},
+		"if the validity period is enforced, remove certificates which are not yet valid and refresh when they become valid": {
+			filter:  &trustapi.BundleFilter{EnforceValidityPeriod: pointer.Bool(true)},
+			data:    data,
+			now:     beforeCertificate2,
+			expData: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{removed(t, dummy.TestCertificate2, removedReasonNotYetValid)}
+			},
+			expNextRefresh: func(t *testing.T) time.Time {
+				return parse(t, dummy.TestCertificate2).NotBefore
+			},
+		},
+		"if the validity period is enforced, remove expired certificates and refresh when the next certificate expires": {
+			filter:  &trustapi.BundleFilter{EnforceValidityPeriod: pointer.Bool(true)},
+			data:    data,
+			now:     afterCertificate2,
+			expData: dummy.JoinCerts(dummy.TestCertificate3),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{
+					removed(t, dummy.TestCertificate1, removedReasonExpired),
+					removed(t, dummy.TestCertificate2, removedReasonExpired),
+				}
+			},
+			expNextRefresh: func(t *testing.T) time.Time {
+				return parse(t, dummy.TestCertificate3).NotAfter.Add(time.Second)
+			},
+		},
+		"if exclude rules are given, remove matching certificates and count them for the first matching rule": {
+			filter: &trustapi.BundleFilter{Exclude: []trustapi.CertificateMatcher{
+				{Subject: "CN=ISRG Root X1,O=Internet Security Research Group,C=US"},
+				{SubjectRegex: "^CN=ISRG Root"},
+				{SHA256: strings.ToUpper(fingerprint(dummy.TestCertificate1))},
+			}},
+			data:    dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate4),
+			now:     beforeCertificate2,
+			expData: dummy.JoinCerts(dummy.TestCertificate2),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{
+					removed(t, dummy.TestCertificate1, removedReasonExcluded),
+					removed(t, dummy.TestCertificate3, removedReasonExcluded),
+					removed(t, dummy.TestCertificate4, removedReasonExcluded),
+				}
+			},
+			expFilterRules: []trustapi.FilterRuleStatus{
+				{Rule: "exclude[0]", Removed: 1},
+				{Rule: "exclude[1]", Removed: 1},
+				{Rule: "exclude[2]", Removed: 1},
+			},
+		},
+		"if include rules are given, remove certificates matching no rule": {
+			filter: &trustapi.BundleFilter{
+				Include: []trustapi.CertificateMatcher{{SubjectRegex: "O=Internet Security Research Group"}},
+				Exclude: []trustapi.CertificateMatcher{{Subject: "CN=ISRG Root X2,O=Internet Security Research Group,C=US"}},
+			},
+			data:    dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate4, dummy.TestCertificate5),
+			now:     beforeCertificate2,
+			expData: dummy.JoinCerts(dummy.TestCertificate3),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{
+					removed(t, dummy.TestCertificate1, removedReasonNotIncluded),
+					removed(t, dummy.TestCertificate4, removedReasonExcluded),
+					removed(t, dummy.TestCertificate5, removedReasonNotIncluded),
+				}
+			},
+			expFilterRules: []trustapi.FilterRuleStatus{
+				{Rule: "exclude[0]", Removed: 1},
+				{Rule: "include", Removed: 2},
+			},
+		},
+		"if a subject regex is invalid, return an error": {
+			filter:   &trustapi.BundleFilter{Exclude: []trustapi.CertificateMatcher{{SubjectRegex: "("}}},
+			data:     data,
+			now:      beforeCertificate2,
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{clock: fakeclock.NewFakeClock(test.now)}
+
+			certificates, err := parseCertificates(test.data)
+			assert.NoError(t, err)
+
+			var resolvedBundle bundleData
+			kept, err := b.filterCertificates(test.filter, certificates, &resolvedBundle)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			if test.expError {
+				return
+			}
+
+			var got string
+			for _, certificate := range kept {
+				got += certificate.pem
+			}
+
+			assert.Equal(t, test.expData, got)
+			assert.Equal(t, test.expFilterRules, resolvedBundle.filterRules)
+
+			var expRemoved []trustapi.RemovedCertificate
+			if test.expRemoved != nil {
+				expRemoved = test.expRemoved(t)
+			}
+			assert.Equal(t, expRemoved, resolvedBundle.removedCertificates)
+
+			var expNextRefresh time.Time
+			if test.expNextRefresh != nil {
+				expNextRefresh = test.expNextRefresh(t)
+			}
+			assert.Equal(t, expNextRefresh, resolvedBundle.nextRefresh)
+		})
+	}
+}
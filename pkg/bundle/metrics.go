@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/fspkg"
+)
+
+var (
+	certificateExpiryDesc = prometheus.NewDesc(
+		"trust_manager_bundle_certificate_expiration_timestamp_seconds",
+		"The time after which a certificate in the Bundle expires, in seconds since the Unix epoch.",
+		[]string{"bundle", "fingerprint", "subject"}, nil,
+	)
+
+	certificatesDesc = prometheus.NewDesc(
+		"trust_manager_bundle_certificates",
+		"The number of certificates in the Bundle.",
+		[]string{"bundle"}, nil,
+	)
+
+	namespacesDesc = prometheus.NewDesc(
+		"trust_manager_bundle_namespaces",
+		"The number of Namespaces which the Bundle targets were synced to, skipped or failed to sync to in the last sync.",
+		[]string{"bundle", "state"}, nil,
+	)
+
+	defaultPackageInfoDesc = prometheus.NewDesc(
+		"trust_manager_default_package_info",
+		"Information about the default CA package which was loaded at startup. The value is always 1.",
+		[]string{"name", "version"}, nil,
+	)
+)
+
+// metricsCollector is a Prometheus collector exposing the contents and sync
+// health of every Bundle, as last observed by the Bundle controller.
+// A nil metricsCollector records nothing.
+type metricsCollector struct {
+	lock sync.Mutex
+
+	// bundles holds the last observed state of each Bundle, keyed by name.
+	bundles map[string]bundleMetrics
+
+	// defaultPackage is the default CA package, if one was loaded at startup.
+	defaultPackage *fspkg.Package
+
+	syncDuration *prometheus.HistogramVec
+}
+
+// bundleMetrics is the last observed state of a single Bundle.
+type bundleMetrics struct {
+	certificates []certificate
+	namespaces   trustapi.NamespaceSyncStatus
+}
+
+var _ prometheus.Collector = &metricsCollector{}
+
+// newMetricsCollector returns a metricsCollector which exposes the given
+// default package, which may be nil.
+func newMetricsCollector(defaultPackage *fspkg.Package) *metricsCollector {
+	return &metricsCollector{
+		bundles:        make(map[string]bundleMetrics),
+		defaultPackage: defaultPackage,
+		syncDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "trust_manager_bundle_sync_duration_seconds",
+			Help:    "The time taken to sync a Bundle to its targets, in seconds.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"bundle"}),
+	}
+}
+
+// registerMetricsCollector registers the given collector with the
+// controller-runtime metrics registry, which is served on the metrics port.
+// If a collector was registered already, the registered collector is
+// returned instead.
+func registerMetricsCollector(collector *metricsCollector) (*metricsCollector, error) {
+	err := metrics.Registry.Register(collector)
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(*metricsCollector); ok {
+			return existing, nil
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to register bundle metrics: %w", err)
+	}
+
+	return collector, nil
+}
+
+// observeBundle records the certificates in the given Bundle and the result
+// of syncing it to Namespaces.
+func (m *metricsCollector) observeBundle(name string, certificates []certificate, namespaces *trustapi.NamespaceSyncStatus) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.bundles[name] = bundleMetrics{
+		certificates: certificates,
+		namespaces:   *namespaces,
+	}
+}
+
+// observeSyncDuration records the time taken to sync the given Bundle.
+func (m *metricsCollector) observeSyncDuration(name string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.syncDuration.WithLabelValues(name).Observe(duration.Seconds())
+}
+
+// forgetBundle removes all metrics of the given Bundle, once it has been
+// deleted.
+func (m *metricsCollector) forgetBundle(name string) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.bundles, name)
+	m.syncDuration.DeleteLabelValues(name)
+}
+
+// Describe implements prometheus.Collector.
+func (m *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- certificateExpiryDesc
+	ch <- certificatesDesc
+	ch <- namespacesDesc
+	ch <- defaultPackageInfoDesc
+	m.syncDuration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for name, bundle := range m.bundles {
+		for _, certificate := range bundle.certificates {
+			ch <- prometheus.MustNewConstMetric(certificateExpiryDesc, prometheus.GaugeValue,
+				float64(certificate.cert.NotAfter.Unix()), name, certificate.fingerprint, certificate.cert.Subject.String())
+		}
+
+		ch <- prometheus.MustNewConstMetric(certificatesDesc, prometheus.GaugeValue, float64(len(bundle.certificates)), name)
+
+		ch <- prometheus.MustNewConstMetric(namespacesDesc, prometheus.GaugeValue, float64(bundle.namespaces.Synced), name, "synced")
+		ch <- prometheus.MustNewConstMetric(namespacesDesc, prometheus.GaugeValue, float64(bundle.namespaces.Skipped), name, "skipped")
+		ch <- prometheus.MustNewConstMetric(namespacesDesc, prometheus.GaugeValue, float64(bundle.namespaces.Failed), name, "failed")
+	}
+
+	if m.defaultPackage != nil {
+		ch <- prometheus.MustNewConstMetric(defaultPackageInfoDesc, prometheus.GaugeValue, 1, m.defaultPackage.Name, m.defaultPackage.Version)
+	}
+
+	m.syncDuration.Collect(ch)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_metricsCollector(t *testing.T) {
+	certificates, err := parseCertificates(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3))
+	require.NoError(t, err)
+
+	collector := newMetricsCollector(&fspkg.Package{Name: "testpkg", Version: "123"})
+
+	collector.observeBundle("test-bundle", certificates, &trustapi.NamespaceSyncStatus{Synced: 3, Skipped: 2, Failed: 1})
+	collector.observeSyncDuration("test-bundle", 50*time.Millisecond)
+
+	expected := fmt.Sprintf(`
+# HELP trust_manager_bundle_certificate_expiration_timestamp_seconds The time after which a certificate in the Bundle expires, in seconds since the Unix epoch.
+# TYPE trust_manager_bundle_certificate_expiration_timestamp_seconds gauge
+trust_manager_bundle_certificate_expiration_timestamp_seconds{bundle="test-bundle",fingerprint="%s",subject="%s"} %d
+trust_manager_bundle_certificate_expiration_timestamp_seconds{bundle="test-bundle",fingerprint="%s",subject="%s"} %d
+# HELP trust_manager_bundle_certificates The number of certificates in the Bundle.
+# TYPE trust_manager_bundle_certificates gauge
+trust_manager_bundle_certificates{bundle="test-bundle"} 2
+# HELP trust_manager_bundle_namespaces The number of Namespaces which the Bundle targets were synced to, skipped or failed to sync to in the last sync.
+# TYPE trust_manager_bundle_namespaces gauge
+trust_manager_bundle_namespaces{bundle="test-bundle",state="failed"} 1
+trust_manager_bundle_namespaces{bundle="test-bundle",state="skipped"} 2
+trust_manager_bundle_namespaces{bundle="test-bundle",state="synced"} 3
+# HELP trust_manager_default_package_info Information about the default CA package which was loaded at startup. The value is always 1.
+# TYPE trust_manager_default_package_info gauge
+trust_manager_default_package_info{name="testpkg",version="123"} 1
+`,
+		certificates[0].fingerprint, certificates[0].cert.Subject, certificates[0].cert.NotAfter.Unix(),
+		certificates[1].fingerprint, certificates[1].cert.Subject, certificates[1].cert.NotAfter.Unix(),
+	)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"trust_manager_bundle_certificate_expiration_timestamp_seconds",
+		"trust_manager_bundle_certificates",
+		"trust_manager_bundle_namespaces",
+		"trust_manager_default_package_info",
+	))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "trust_manager_bundle_sync_duration_seconds"))
+
+	collector.forgetBundle("test-bundle")
+
+	assert.Equal(t, 1, testutil.CollectAndCount(collector), "expected only the default package info after the Bundle was forgotten")
+}
+
+func Test_metricsCollectorNil(t *testing.T) {
+	var collector *metricsCollector
+
+	// A nil collector must record nothing, without panicking.
+	collector.observeBundle("test-bundle", nil, &trustapi.NamespaceSyncStatus{})
+	collector.observeSyncDuration("test-bundle", time.Second)
+	collector.forgetBundle("test-bundle")
+}
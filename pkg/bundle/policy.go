@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// removedReasonRSAKeyTooSmall is the reason given for certificates which
+	// were removed because their RSA public key is smaller than the minimum
+	// size of the crypto policy.
+	removedReasonRSAKeyTooSmall = "RSAKeyTooSmall"
+
+	// removedReasonCurveNotAllowed is the reason given for certificates which
+	// were removed because the curve of their public key is not allowed by the
+	// crypto policy.
+	removedReasonCurveNotAllowed = "CurveNotAllowed"
+
+	// removedReasonSignatureAlgorithmForbidden is the reason given for
+	// certificates which were removed because their signature algorithm is
+	// forbidden by the crypto policy.
+	removedReasonSignatureAlgorithmForbidden = "SignatureAlgorithmForbidden"
+)
+
+// applyCryptoPolicy returns the certificates which comply with the given
+// crypto policy. If the policy mode is Fail and any certificate violates the
+// policy, a cryptoPolicyError describing all violations is returned.
+// Otherwise the removed certificates are recorded in resolvedBundle.
+func applyCryptoPolicy(policy *trustapi.CryptoPolicy, certificates []certificate, resolvedBundle *bundleData) ([]certificate, error) {
+	var (
+		kept       []certificate
+		removed    []trustapi.RemovedCertificate
+		violations []string
+	)
+
+	for _, certificate := range certificates {
+		reason, message := cryptoPolicyViolation(policy, certificate)
+		if len(reason) == 0 {
+			kept = append(kept, certificate)
+			continue
+		}
+
+		removed = append(removed, trustapi.RemovedCertificate{
+			Subject: certificate.cert.Subject.String(),
+			SHA256:  certificate.fingerprint,
+			Reason:  reason,
+		})
+
+		violations = append(violations, fmt.Sprintf("%q: %s", certificate.cert.Subject.String(), message))
+	}
+
+	if len(violations) == 0 {
+		return kept, nil
+	}
+
+	if policy.Mode == trustapi.CryptoPolicyModeFail {
+		return nil, cryptoPolicyError{errors.New(strings.Join(violations, "; "))}
+	}
+
+	resolvedBundle.removedCertificates = append(resolvedBundle.removedCertificates, removed...)
+	resolvedBundle.cryptoPolicyViolations = append(resolvedBundle.cryptoPolicyViolations, violations...)
+
+	return kept, nil
+}
+
+// cryptoPolicyViolation returns the reason and a human readable message if
+// the given certificate violates the crypto policy, or empty strings if it
+// complies.
+func cryptoPolicyViolation(policy *trustapi.CryptoPolicy, certificate certificate) (string, string) {
+	cert := certificate.cert
+
+	switch publicKey := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if policy.MinRSAKeySize != nil && publicKey.N.BitLen() < int(*policy.MinRSAKeySize) {
+			return removedReasonRSAKeyTooSmall, fmt.Sprintf("RSA key size %d is smaller than the minimum of %d", publicKey.N.BitLen(), *policy.MinRSAKeySize)
+		}
+
+	case *ecdsa.PublicKey:
+		if curve := publicKey.Curve.Params().Name; !curveAllowed(policy, curve) {
+			return removedReasonCurveNotAllowed, fmt.Sprintf("curve %s is not allowed", curve)
+		}
+
+	case ed25519.PublicKey:
+		if !curveAllowed(policy, "Ed25519") {
+			return removedReasonCurveNotAllowed, "curve Ed25519 is not allowed"
+		}
+	}
+
+	for _, algorithm := range policy.ForbiddenSignatureAlgorithms {
+		if cert.SignatureAlgorithm.String() == algorithm {
+			return removedReasonSignatureAlgorithmForbidden, fmt.Sprintf("signature algorithm %s is forbidden", algorithm)
+		}
+	}
+
+	return "", ""
+}
+
+// curveAllowed returns true if the given curve is allowed by the crypto
+// policy. All curves are allowed if the policy doesn't list any.
+func curveAllowed(policy *trustapi.CryptoPolicy, curve string) bool {
+	if len(policy.AllowedCurves) == 0 {
+		return true
+	}
+
+	for _, allowed := range policy.AllowedCurves {
+		if allowed == curve {
+			return true
+		}
+	}
+
+	return false
+}
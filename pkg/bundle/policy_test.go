@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_applyCryptoPolicy(t *testing.T) {
+	removed := func(t *testing.T, certPEM, reason string) trustapi.RemovedCertificate {
+		certificates, err := parseCertificates(certPEM)
+		if err != nil || len(certificates) != 1 {
+			t.Fatalf("failed to parse dummy certificate: %v", err)
+		}
+
+		return trustapi.RemovedCertificate{
+			Subject: certificates[0].cert.Subject.String(),
+			SHA256:  certificates[0].fingerprint,
+			Reason:  reason,
+		}
+	}
+
+	// TestCertificate1 has a P-256 key, TestCertificate2 an Ed25519 key,
+	// TestCertificate3 a 4096 bit RSA key and TestCertificate4 a P-384 key.
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate4)
+
+	tests := map[string]struct {
+		policy *trustapi.CryptoPolicy
+
+		expData          string
+		expRemoved       func(t *testing.T) []trustapi.RemovedCertificate
+		expViolations    int
+		expPolicyFailure bool
+	}{
+		"if the policy is empty, keep all certificates": {
+			policy:  &trustapi.CryptoPolicy{},
+			expData: data,
+		},
+		"if RSA keys are smaller than the minimum size, remove them": {
+			policy:  &trustapi.CryptoPolicy{MinRSAKeySize: pointer.Int32(8192)},
+			expData: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate4),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{removed(t, dummy.TestCertificate3, removedReasonRSAKeyTooSmall)}
+			},
+			expViolations: 1,
+		},
+		"if RSA keys are at least the minimum size, keep them": {
+			policy:  &trustapi.CryptoPolicy{MinRSAKeySize: pointer.Int32(2048)},
+			expData: data,
+		},
+		"if curves are not allowed, remove certificates using them": {
+			policy:  &trustapi.CryptoPolicy{AllowedCurves: []string{"P-384"}},
+			expData: dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate4),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{
+					removed(t, dummy.TestCertificate1, removedReasonCurveNotAllowed),
+					removed(t, dummy.TestCertificate2, removedReasonCurveNotAllowed),
+				}
+			},
+			expViolations: 2,
+		},
+		"if signature algorithms are forbidden, remove certificates signed with them": {
+			policy:  &trustapi.CryptoPolicy{ForbiddenSignatureAlgorithms: []string{"SHA256-RSA", "ECDSA-SHA384"}},
+			expData: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expRemoved: func(t *testing.T) []trustapi.RemovedCertificate {
+				return []trustapi.RemovedCertificate{
+					removed(t, dummy.TestCertificate3, removedReasonSignatureAlgorithmForbidden),
+					removed(t, dummy.TestCertificate4, removedReasonSignatureAlgorithmForbidden),
+				}
+			},
+			expViolations: 2,
+		},
+		"if the mode is Fail and certificates violate the policy, return a crypto policy error": {
+			policy: &trustapi.CryptoPolicy{
+				MinRSAKeySize: pointer.Int32(8192),
+				Mode:          trustapi.CryptoPolicyModeFail,
+			},
+			expPolicyFailure: true,
+		},
+		"if the mode is Fail and no certificate violates the policy, keep all certificates": {
+			policy: &trustapi.CryptoPolicy{
+				MinRSAKeySize: pointer.Int32(2048),
+				Mode:          trustapi.CryptoPolicyModeFail,
+			},
+			expData: data,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificates, err := parseCertificates(data)
+			assert.NoError(t, err)
+
+			var resolvedBundle bundleData
+			kept, err := applyCryptoPolicy(test.policy, certificates, &resolvedBundle)
+			assert.Equal(t, test.expPolicyFailure, errors.As(err, &cryptoPolicyError{}), "unexpected error: %v", err)
+			if test.expPolicyFailure {
+				assert.Empty(t, resolvedBundle.removedCertificates)
+				return
+			}
+
+			var got string
+			for _, certificate := range kept {
+				got += certificate.pem
+			}
+
+			assert.Equal(t, test.expData, got)
+
+			var expRemoved []trustapi.RemovedCertificate
+			if test.expRemoved != nil {
+				expRemoved = test.expRemoved(t)
+			}
+			assert.Equal(t, expRemoved, resolvedBundle.removedCertificates)
+			assert.Len(t, resolvedBundle.cryptoPolicyViolations, test.expViolations)
+		})
+	}
+}
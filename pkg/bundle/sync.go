@@ -21,19 +21,30 @@ import (
 	"context"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	jks "github.com/pavlo-v-chernykh/keystore-go/v4"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"software.sslmate.com/src/go-pkcs12"
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/util"
@@ -44,10 +55,30 @@ const (
 	// Since we're not storing anything secret in the JKS files we generate, this password is not a meaningful security measure
 	// but seems often to be expected by applications consuming JKS files
 	DefaultJKSPassword = "changeit"
+
+	// BundleHashAnnotationKey is the annotation on Bundle targets holding the
+	// hash of the data which was last written to the target.
+	BundleHashAnnotationKey = "trust.cert-manager.io/hash"
+
+	// FieldManager is the field manager which trust-manager uses to apply
+	// Bundle targets, so that it only owns the fields which it writes.
+	FieldManager = "trust-manager"
 )
 
 type notFoundError struct{ error }
 
+// cycleError is returned when the Bundle sources of a Bundle reference each
+// other in a cycle, and so can never be resolved.
+type cycleError struct{ error }
+
+// cryptoPolicyError is returned when certificates of a Bundle violate its
+// crypto policy and the policy mode is Fail.
+type cryptoPolicyError struct{ error }
+
+// caRequirementError is returned when certificates of a Bundle don't meet
+// its CA requirement.
+type caRequirementError struct{ error }
+
 // bundleData holds the result of a call to buildSourceBundle. It contains both the resulting PEM-encoded
 // certificate data from concatenating all of the sources together and any metadata from the sources which
 // needs to be exposed on the Bundle resource's status field.
@@ -55,16 +86,61 @@ type bundleData struct {
 	data string
 
 	defaultCAPackageStringID string
+
+	// nextRefresh is the earliest time at which a source needs to be resolved
+	// again, for example because a URL source is due to be fetched. Zero if
+	// no source needs to be refreshed.
+	nextRefresh time.Time
+
+	// degradedSources holds a message for each source which could not be
+	// refreshed, and for which the last known good data was used instead.
+	degradedSources []string
+
+	// removedCertificates holds the certificates which were removed from the
+	// source data by the Bundle filter or crypto policy.
+	removedCertificates []trustapi.RemovedCertificate
+
+	// filterRules holds the number of certificates removed by each include
+	// and exclude rule of the Bundle filter.
+	filterRules []trustapi.FilterRuleStatus
+
+	// cryptoPolicyViolations describes the certificates which were removed
+	// from the source data by the Bundle crypto policy.
+	cryptoPolicyViolations []string
+
+	// certificates summarises the certificates in the data, and in the data
+	// of each source.
+	certificates *trustapi.CertificatesStatus
+
+	// parsedCertificates holds the certificates in the data, in order.
+	parsedCertificates []certificate
+}
+
+// refreshAt records that the bundle data needs to be resolved again at the
+// given time, if that is earlier than any previously recorded time.
+func (d *bundleData) refreshAt(t time.Time) {
+	if d.nextRefresh.IsZero() || t.Before(d.nextRefresh) {
+		d.nextRefresh = t
+	}
 }
 
 // buildSourceBundle retrieves and concatenates all source bundle data for this Bundle object.
 // Each source data is validated and pruned to ensure that all certificates within are valid, and
 // is each bundle is concatenated together with a new line character.
 func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle) (bundleData, error) {
+	return b.buildSourceBundleWithChain(ctx, bundle, nil)
+}
+
+// buildSourceBundleWithChain builds the source bundle for the given Bundle.
+// chain holds the names of the Bundles which are currently being resolved and
+// which (transitively) reference this Bundle as a source. It is used to
+// detect Bundles which reference each other in a cycle.
+func (b *bundle) buildSourceBundleWithChain(ctx context.Context, bundle *trustapi.Bundle, chain []string) (bundleData, error) {
 	var resolvedBundle bundleData
 	var bundles []string
+	var sources []trustapi.SourceCertificatesStatus
 
-	for _, source := range bundle.Spec.Sources {
+	for i, source := range bundle.Spec.Sources {
 		var (
 			sourceData string
 			err        error
@@ -80,6 +156,24 @@ func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle)
 		case source.InLine != nil:
 			sourceData = *source.InLine
 
+		case source.Bundle != nil:
+			var dependency bundleData
+			dependency, err = b.bundleBundle(ctx, bundle, source.Bundle, chain)
+			sourceData = dependency.data
+			if len(dependency.defaultCAPackageStringID) > 0 {
+				resolvedBundle.defaultCAPackageStringID = dependency.defaultCAPackageStringID
+			}
+			if !dependency.nextRefresh.IsZero() {
+				resolvedBundle.refreshAt(dependency.nextRefresh)
+			}
+			resolvedBundle.degradedSources = append(resolvedBundle.degradedSources, dependency.degradedSources...)
+
+		case source.URL != nil:
+			sourceData, err = b.urlBundle(ctx, source.URL, &resolvedBundle)
+
+		case source.ClusterTrustBundle != nil:
+			sourceData, err = b.clusterTrustBundleBundle(ctx, source.ClusterTrustBundle)
+
 		case source.UseDefaultCAs != nil:
 			if *source.UseDefaultCAs == false {
 				continue
@@ -102,6 +196,12 @@ func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle)
 			return bundleData{}, fmt.Errorf("invalid PEM data in source: %w", err)
 		}
 
+		sourceCertificates, err := parseCertificates(string(sanitizedBundle))
+		if err != nil {
+			return bundleData{}, fmt.Errorf("invalid PEM data in source: %w", err)
+		}
+
+		sources = append(sources, sourceCertificatesStatus(fmt.Sprintf("sources[%d]", i), sourceCertificates))
 		bundles = append(bundles, string(sanitizedBundle))
 	}
 
@@ -111,58 +211,226 @@ func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle)
 		return bundleData{}, fmt.Errorf("couldn't find any valid certificates in bundle")
 	}
 
-	resolvedBundle.data = strings.Join(bundles, "\n") + "\n"
+	data, err := b.processBundle(bundle.Spec, strings.Join(bundles, "\n")+"\n", &resolvedBundle)
+	if err != nil {
+		return bundleData{}, err
+	}
+
+	resolvedBundle.data = data
+	resolvedBundle.certificates.Sources = sources
 
 	return resolvedBundle, nil
 }
 
 // configMapBundle returns the data in the source ConfigMap within the trust Namespace.
+// If the reference uses a label selector, the data of all matching ConfigMaps is
+// concatenated in order of their names.
 func (b *bundle) configMapBundle(ctx context.Context, ref *trustapi.SourceObjectKeySelector) (string, error) {
-	var configMap corev1.ConfigMap
-	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &configMap)
-	if apierrors.IsNotFound(err) {
-		return "", notFoundError{err}
-	}
+	var configMaps []corev1.ConfigMap
 
-	if err != nil {
-		return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", b.Namespace, ref.Name, err)
+	if len(ref.Name) > 0 {
+		var configMap corev1.ConfigMap
+		err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &configMap)
+		if apierrors.IsNotFound(err) {
+			return "", notFoundError{err}
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", b.Namespace, ref.Name, err)
+		}
+
+		configMaps = []corev1.ConfigMap{configMap}
+	} else {
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse label selector for ConfigMaps in %s: %w", b.Namespace, err)
+		}
+
+		var configMapList corev1.ConfigMapList
+		if err := b.sourceLister.List(ctx, &configMapList, client.InNamespace(b.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return "", fmt.Errorf("failed to list ConfigMaps in %s with selector %s: %w", b.Namespace, selector, err)
+		}
+
+		if len(configMapList.Items) == 0 {
+			return "", notFoundError{fmt.Errorf("no ConfigMaps found in %s matching selector %s", b.Namespace, selector)}
+		}
+
+		configMaps = configMapList.Items
+		sort.Slice(configMaps, func(i, j int) bool {
+			return configMaps[i].Name < configMaps[j].Name
+		})
 	}
 
-	data, ok := configMap.Data[ref.Key]
-	if !ok {
-		return "", notFoundError{fmt.Errorf("no data found in ConfigMap %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+	var results []string
+	for _, configMap := range configMaps {
+		data, ok := configMap.Data[ref.Key]
+		if !ok {
+			return "", notFoundError{fmt.Errorf("no data found in ConfigMap %s/%s at key %q", b.Namespace, configMap.Name, ref.Key)}
+		}
+
+		results = append(results, data)
 	}
 
-	return data, nil
+	return strings.Join(results, "\n"), nil
 }
 
-// secretBundle returns the data in the target Secret within the trust Namespace.
+// secretBundle returns the data in the source Secret within the trust Namespace.
+// If the reference uses a label selector, the data of all matching Secrets is
+// concatenated in order of their names.
 func (b *bundle) secretBundle(ctx context.Context, ref *trustapi.SourceObjectKeySelector) (string, error) {
-	var secret corev1.Secret
-	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+	var secrets []corev1.Secret
+
+	if len(ref.Name) > 0 {
+		var secret corev1.Secret
+		err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+		if apierrors.IsNotFound(err) {
+			return "", notFoundError{err}
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+		}
+
+		secrets = []corev1.Secret{secret}
+	} else {
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse label selector for Secrets in %s: %w", b.Namespace, err)
+		}
+
+		var secretList corev1.SecretList
+		if err := b.sourceLister.List(ctx, &secretList, client.InNamespace(b.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return "", fmt.Errorf("failed to list Secrets in %s with selector %s: %w", b.Namespace, selector, err)
+		}
+
+		if len(secretList.Items) == 0 {
+			return "", notFoundError{fmt.Errorf("no Secrets found in %s matching selector %s", b.Namespace, selector)}
+		}
+
+		secrets = secretList.Items
+		sort.Slice(secrets, func(i, j int) bool {
+			return secrets[i].Name < secrets[j].Name
+		})
+	}
+
+	var results []string
+	for _, secret := range secrets {
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, secret.Name, ref.Key)}
+		}
+
+		results = append(results, string(data))
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// clusterTrustBundleBundle returns the trust bundle of the referenced
+// ClusterTrustBundle. If the reference selects ClusterTrustBundles by signer,
+// the trust bundles of all matching ClusterTrustBundles are concatenated in
+// order of their names.
+func (b *bundle) clusterTrustBundleBundle(ctx context.Context, ref *trustapi.ClusterTrustBundleSource) (string, error) {
+	var clusterTrustBundles []certificatesv1alpha1.ClusterTrustBundle
+
+	if len(ref.Name) > 0 {
+		var clusterTrustBundle certificatesv1alpha1.ClusterTrustBundle
+		err := b.sourceLister.Get(ctx, client.ObjectKey{Name: ref.Name}, &clusterTrustBundle)
+		if apierrors.IsNotFound(err) {
+			return "", notFoundError{err}
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("failed to get ClusterTrustBundle %s: %w", ref.Name, err)
+		}
+
+		clusterTrustBundles = []certificatesv1alpha1.ClusterTrustBundle{clusterTrustBundle}
+	} else {
+		selector, err := clusterTrustBundleSourceSelector(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse label selector for ClusterTrustBundles of signer %s: %w", ref.SignerName, err)
+		}
+
+		var clusterTrustBundleList certificatesv1alpha1.ClusterTrustBundleList
+		if err := b.sourceLister.List(ctx, &clusterTrustBundleList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return "", fmt.Errorf("failed to list ClusterTrustBundles with selector %s: %w", selector, err)
+		}
+
+		for _, clusterTrustBundle := range clusterTrustBundleList.Items {
+			if clusterTrustBundle.Spec.SignerName == ref.SignerName {
+				clusterTrustBundles = append(clusterTrustBundles, clusterTrustBundle)
+			}
+		}
+
+		if len(clusterTrustBundles) == 0 {
+			return "", notFoundError{fmt.Errorf("no ClusterTrustBundles found for signer %s matching selector %s", ref.SignerName, selector)}
+		}
+
+		sort.Slice(clusterTrustBundles, func(i, j int) bool {
+			return clusterTrustBundles[i].Name < clusterTrustBundles[j].Name
+		})
+	}
+
+	var results []string
+	for _, clusterTrustBundle := range clusterTrustBundles {
+		results = append(results, clusterTrustBundle.Spec.TrustBundle)
+	}
+
+	return strings.Join(results, "\n"), nil
+}
+
+// clusterTrustBundleSourceSelector returns the label selector of the given
+// ClusterTrustBundle source. A nil selector selects all ClusterTrustBundles
+// of the signer.
+func clusterTrustBundleSourceSelector(ref *trustapi.ClusterTrustBundleSource) (labels.Selector, error) {
+	if ref.Selector == nil {
+		return labels.Everything(), nil
+	}
+
+	return metav1.LabelSelectorAsSelector(ref.Selector)
+}
+
+// bundleBundle returns the resolved source data of the Bundle referenced by
+// the given Bundle. Returns a cycleError if the referenced Bundle is already
+// being resolved further up the chain.
+func (b *bundle) bundleBundle(ctx context.Context, bundle *trustapi.Bundle, ref *trustapi.BundleReference, chain []string) (bundleData, error) {
+	chain = append(chain[:len(chain):len(chain)], bundle.Name)
+
+	for _, name := range chain {
+		if name == ref.Name {
+			return bundleData{}, cycleError{fmt.Errorf("bundle source cycle detected: %s -> %s", strings.Join(chain, " -> "), ref.Name)}
+		}
+	}
+
+	var dependency trustapi.Bundle
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Name: ref.Name}, &dependency)
 	if apierrors.IsNotFound(err) {
-		return "", notFoundError{err}
+		return bundleData{}, notFoundError{err}
 	}
+
 	if err != nil {
-		return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+		return bundleData{}, fmt.Errorf("failed to get Bundle %s: %w", ref.Name, err)
 	}
 
-	data, ok := secret.Data[ref.Key]
-	if !ok {
-		return "", notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+	resolved, err := b.buildSourceBundleWithChain(ctx, &dependency, chain)
+	if err != nil {
+		return bundleData{}, fmt.Errorf("failed to resolve Bundle %s: %w", ref.Name, err)
 	}
 
-	return string(data), nil
+	return resolved, nil
 }
 
 // encodeJKS creates a binary JKS file from the given PEM-encoded trust bundle and password.
 // Note that the password is not treated securely; JKS files generally seem to expect a password
 // to exist and so we have the option for one.
+// The encoding is deterministic, so that targets can be compared with the expected JKS file.
 func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
 	remaining := []byte(trustBundle)
 
-	// WithOrderedAliases ensures that trusted certs are added to the JKS file in order,
-	// which makes the files appear to be reliably deterministic.
+	// WithOrderedAliases writes the entries sorted by alias. Together with the fixed
+	// creation times below, this makes the same trust bundle and password always
+	// result in a byte-for-byte identical JKS file. Trusted certificate entries are
+	// not encrypted, so encoding them doesn't read any random data.
 	ks := jks.New(jks.WithOrderedAliases())
 
 	for len(remaining) > 0 {
@@ -178,7 +446,7 @@ func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
 			return nil, fmt.Errorf("got invalid cert when trying to encode JKS: %w", err)
 		}
 
-		alias := jksAlias(c.Raw, c.Subject.String())
+		alias := certAlias(c.Raw, c.Subject.String())
 
 		// Note on CreationTime:
 		// Debian's JKS trust store sets the creation time to match the time that certs are added to the
@@ -213,13 +481,360 @@ func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// jksAlias creates a JKS-safe alias for the given DER-encoded certificate, such that
+// encodePKCS12 creates a binary PKCS12 trust store from the given PEM-encoded trust bundle
+// and password. Certificates are added in the order of the trust bundle using the same aliases
+// as JKS trust stores. If the password is empty, the trust store is neither encrypted nor
+// integrity protected.
+func encodePKCS12(trustBundle string, password string) ([]byte, error) {
+	remaining := []byte(trustBundle)
+
+	var entries []pkcs12.TrustStoreEntry
+	for len(remaining) > 0 {
+		var p *pem.Block
+
+		p, remaining = pem.Decode(remaining)
+		if p == nil {
+			break
+		}
+
+		c, err := x509.ParseCertificate(p.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("got invalid cert when trying to encode PKCS12: %w", err)
+		}
+
+		entries = append(entries, pkcs12.TrustStoreEntry{
+			Cert:         c,
+			FriendlyName: certAlias(c.Raw, c.Subject.String()),
+		})
+	}
+
+	encoder := pkcs12.Passwordless
+	if len(password) > 0 {
+		// Password-protected trust stores use AES-256 and an HMAC-SHA-256 MAC,
+		// which can be read by Java 12, OpenSSL 1.1.1 and newer.
+		// The salts and IVs used when encrypting the trust store are read from a
+		// reader seeded with the password and trust bundle rather than from a
+		// random source, so that the same trust bundle and password always
+		// result in the same trust store. The contents of a trust store are
+		// public, so this doesn't weaken it, and trust stores with different
+		// passwords never share salts.
+		encoder = pkcs12.Modern2023.WithRand(newDeterministicReader(pkcs12Seed(trustBundle, password)))
+	}
+
+	pfxData, err := encoder.EncodeTrustStoreEntries(entries, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PKCS12 file: %w", err)
+	}
+
+	return pfxData, nil
+}
+
+// encodeDER creates a concatenation of the DER-encoded certificates of the
+// given PEM-encoded trust bundle, in the order of the trust bundle.
+func encodeDER(trustBundle string) []byte {
+	remaining := []byte(trustBundle)
+
+	var derData []byte
+	for len(remaining) > 0 {
+		var p *pem.Block
+
+		p, remaining = pem.Decode(remaining)
+		if p == nil {
+			break
+		}
+
+		derData = append(derData, p.Bytes...)
+	}
+
+	return derData
+}
+
+var (
+	oidPKCS7Data       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7SignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+// pkcs7ContentInfo is the ContentInfo structure of RFC 2315.
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+// pkcs7SignedData is the SignedData structure of RFC 2315. The certificates
+// field is an implicitly tagged SET OF Certificate, which is written as a
+// context-specific raw value.
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      asn1.RawValue
+}
+
+// encodePKCS7 creates a DER-encoded, certificates-only PKCS#7 SignedData
+// structure from the given PEM-encoded trust bundle, as written by
+// `openssl crl2pkcs7 -nocrl`. It contains no content and no signatures.
+func encodePKCS7(trustBundle string) ([]byte, error) {
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true}
+
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidPKCS7Data},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: encodeDER(trustBundle)},
+		SignerInfos:      emptySet,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PKCS7 file: %w", err)
+	}
+
+	pkcs7Data, err := asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidPKCS7SignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PKCS7 file: %w", err)
+	}
+
+	return pkcs7Data, nil
+}
+
+// pkcs12Seed returns the seed of the random data used to encrypt a PKCS12
+// trust store with the given password. The password is length-prefixed, so
+// that different pairs of password and trust bundle never give the same seed.
+func pkcs12Seed(trustBundle string, password string) []byte {
+	seed := binary.BigEndian.AppendUint64(nil, uint64(len(password)))
+	seed = append(seed, password...)
+	return append(seed, trustBundle...)
+}
+
+// deterministicReader is an io.Reader which returns an endless stream of bytes
+// derived from a seed. Two readers with the same seed return the same bytes.
+type deterministicReader struct {
+	seed    [sha256.Size]byte
+	counter uint64
+	buf     []byte
+}
+
+func newDeterministicReader(seed []byte) *deterministicReader {
+	return &deterministicReader{seed: sha256.Sum256(seed)}
+}
+
+// Read fills p with the next bytes of the stream, which are the SHA-256 hashes
+// of the seed concatenated with an incrementing counter.
+func (r *deterministicReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			block := make([]byte, sha256.Size+8)
+			copy(block, r.seed[:])
+			binary.BigEndian.PutUint64(block[sha256.Size:], r.counter)
+			r.counter++
+
+			sum := sha256.Sum256(block)
+			r.buf = sum[:]
+		}
+
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+
+	return n, nil
+}
+
+// targetData holds the data which is written to every target of a Bundle.
+type targetData struct {
+	// data is the PEM-encoded trust bundle.
+	data string
+
+	// stringData maps each additional target key in the PEM format to the
+	// PEM-encoded trust bundle.
+	stringData map[string]string
+
+	// binaryData maps the target key of each additional format to the trust
+	// bundle encoded in that format.
+	binaryData map[string][]byte
+
+	// hash is the SHA-256 digest of all of the above. It is stored in the
+	// BundleHashAnnotationKey annotation of targets, so that targets are
+	// updated whenever the encoding of an additional format changes, for
+	// example because a trust store password was changed.
+	hash string
+}
+
+// buildTargetData encodes the given PEM-encoded trust bundle in all
+// additional formats requested by the Bundle target. Passwords which are
+// referenced from Secrets are read from the trust Namespace.
+func (b *bundle) buildTargetData(ctx context.Context, bundle *trustapi.Bundle, data string) (targetData, error) {
+	target := targetData{data: data}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil {
+		target.binaryData = make(map[string][]byte)
+
+		if formats.JKS != nil {
+			password, err := b.truststorePassword(ctx, formats.JKS.Password, formats.JKS.PasswordSecretRef, DefaultJKSPassword)
+			if err != nil {
+				return targetData{}, fmt.Errorf("failed to get JKS password: %w", err)
+			}
+
+			encoded, err := encodeJKS(data, []byte(password))
+			if err != nil {
+				return targetData{}, err
+			}
+
+			target.binaryData[formats.JKS.Key] = encoded
+		}
+
+		if formats.PKCS12 != nil {
+			password, err := b.truststorePassword(ctx, formats.PKCS12.Password, formats.PKCS12.PasswordSecretRef, "")
+			if err != nil {
+				return targetData{}, fmt.Errorf("failed to get PKCS12 password: %w", err)
+			}
+
+			encoded, err := encodePKCS12(data, password)
+			if err != nil {
+				return targetData{}, err
+			}
+
+			target.binaryData[formats.PKCS12.Key] = encoded
+		}
+	}
+
+	for _, key := range bundle.Spec.Target.Keys {
+		if key.Format == "" || key.Format == trustapi.TargetFormatPEM {
+			if target.stringData == nil {
+				target.stringData = make(map[string]string)
+			}
+
+			target.stringData[key.Key] = data
+			continue
+		}
+
+		encoded, err := b.encodeTargetKey(ctx, key, data)
+		if err != nil {
+			return targetData{}, err
+		}
+
+		if target.binaryData == nil {
+			target.binaryData = make(map[string][]byte)
+		}
+
+		target.binaryData[key.Key] = encoded
+	}
+
+	target.hash = targetDataHash(target.data, target.stringData, target.binaryData)
+
+	return target, nil
+}
+
+// encodeTargetKey encodes the given PEM-encoded trust bundle in the binary
+// format of the given target key.
+func (b *bundle) encodeTargetKey(ctx context.Context, key trustapi.TargetKey, data string) ([]byte, error) {
+	switch key.Format {
+	case trustapi.TargetFormatJKS:
+		password, err := b.truststorePassword(ctx, key.Password, key.PasswordSecretRef, DefaultJKSPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get JKS password for key %q: %w", key.Key, err)
+		}
+
+		return encodeJKS(data, []byte(password))
+
+	case trustapi.TargetFormatPKCS12:
+		password, err := b.truststorePassword(ctx, key.Password, key.PasswordSecretRef, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PKCS12 password for key %q: %w", key.Key, err)
+		}
+
+		return encodePKCS12(data, password)
+
+	case trustapi.TargetFormatDER:
+		return encodeDER(data), nil
+
+	case trustapi.TargetFormatPKCS7:
+		return encodePKCS7(data)
+
+	default:
+		return nil, fmt.Errorf("unsupported format %q for key %q", key.Format, key.Key)
+	}
+}
+
+// truststorePassword returns the password for a binary trust store, which is
+// either given inline or read from a Secret in the trust Namespace. Returns
+// the default password if neither is set.
+func (b *bundle) truststorePassword(ctx context.Context, password *string, secretRef *trustapi.SecretKeySelector, defaultPassword string) (string, error) {
+	if password != nil {
+		return *password, nil
+	}
+
+	if secretRef == nil {
+		return defaultPassword, nil
+	}
+
+	var secret corev1.Secret
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: secretRef.Name}, &secret)
+	if apierrors.IsNotFound(err) {
+		return "", notFoundError{err}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, secretRef.Name, err)
+	}
+
+	value, ok := secret.Data[secretRef.Key]
+	if !ok {
+		return "", notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, secretRef.Name, secretRef.Key)}
+	}
+
+	return string(value), nil
+}
+
+// targetDataHash returns the hex-encoded SHA-256 digest of the given
+// PEM-encoded trust bundle, string data and binary data, with string and
+// binary data keys hashed in sorted order.
+func targetDataHash(data string, stringData map[string]string, binaryData map[string][]byte) string {
+	stringKeys := make([]string, 0, len(stringData))
+	for key := range stringData {
+		stringKeys = append(stringKeys, key)
+	}
+	sort.Strings(stringKeys)
+
+	binaryKeys := make([]string, 0, len(binaryData))
+	for key := range binaryData {
+		binaryKeys = append(binaryKeys, key)
+	}
+	sort.Strings(binaryKeys)
+
+	hash := sha256.New()
+	writeHashField(hash, []byte(data))
+	for _, key := range stringKeys {
+		writeHashField(hash, []byte(key))
+		writeHashField(hash, []byte(stringData[key]))
+	}
+	for _, key := range binaryKeys {
+		writeHashField(hash, []byte(key))
+		writeHashField(hash, binaryData[key])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// writeHashField writes the given field to the hash prefixed with its length,
+// so that the boundaries between fields are unambiguous.
+func writeHashField(hash io.Writer, field []byte) {
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+	_, _ = hash.Write(length[:])
+	_, _ = hash.Write(field)
+}
+
+// certAlias creates a JKS-safe alias for the given DER-encoded certificate, such that
 // any two certificates will have a different aliases unless they're identical in every way.
 // This unique alias fixes an issue where we used the Issuer field as an alias, leading to
 // different certs being treated as identical.
-// The friendlyName is included in the alias as a UX feature when examining JKS files using a
-// tool like `keytool`.
-func jksAlias(derData []byte, friendlyName string) string {
+// The friendlyName is included in the alias as a UX feature when examining JKS or PKCS12
+// files using a tool like `keytool`.
+func certAlias(derData []byte, friendlyName string) string {
 	certHashBytes := sha256.Sum256(derData)
 	certHash := hex.EncodeToString(certHashBytes[:])
 
@@ -231,36 +846,28 @@ func jksAlias(derData []byte, friendlyName string) string {
 	return certHash[:8] + "|" + friendlyName
 }
 
-// syncTarget syncs the given data to the target ConfigMap in the given namespace.
-// The name of the ConfigMap is the same as the Bundle.
+// syncConfigMapTarget syncs the given data to the target ConfigMap in the given namespace.
+// The name of the ConfigMap is the configured target name, or else the name of the Bundle.
 // Ensures the ConfigMap is owned by the given Bundle, and the data is up to date.
 // Returns true if the ConfigMap has been created or was updated.
-func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
+func (b *bundle) syncConfigMapTarget(ctx context.Context, log logr.Logger,
 	bundle *trustapi.Bundle,
 	namespaceSelector labels.Selector,
 	namespace *corev1.Namespace,
-	data string,
+	data targetData,
 ) (bool, error) {
 	target := bundle.Spec.Target
-	var binData *[]byte
 
 	if target.ConfigMap == nil {
 		return false, errors.New("target not defined")
 	}
 
-	matchNamespace := namespaceSelector.Matches(labels.Set(namespace.Labels))
-
-	var configMap corev1.ConfigMap
-	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: bundle.Name}, &configMap)
+	matchNamespace := namespaceSelector.Matches(util.NamespaceLabels(namespace))
 
-	if target.AdditionalFormats != nil && target.AdditionalFormats.JKS != nil {
-		j, err := encodeJKS(data, []byte(DefaultJKSPassword))
-		if err != nil {
-			return false, err
-		}
+	name := configMapTargetName(bundle, &target)
 
-		binData = &j
-	}
+	var configMap corev1.ConfigMap
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: name}, &configMap)
 
 	// If the ConfigMap doesn't exist yet, create it.
 	if apierrors.IsNotFound(err) {
@@ -271,28 +878,11 @@ func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 			return false, nil
 		}
 
-		configMap = corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            bundle.Name,
-				Namespace:       namespace.Name,
-				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
-			},
-			Data: map[string]string{
-				target.ConfigMap.Key: data,
-			},
-		}
-
-		if binData != nil {
-			configMap.BinaryData = map[string][]byte{
-				target.AdditionalFormats.JKS.Key: *binData,
-			}
-		}
-
-		return true, b.targetDirectClient.Create(ctx, &configMap)
+		return true, b.applyConfigMapTarget(ctx, bundle, nil, name, namespace.Name, data)
 	}
 
 	if err != nil {
-		return false, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, bundle.Name, err)
+		return false, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
 	}
 
 	// Here, the config map exists, but the selector doesn't match the namespace.
@@ -308,37 +898,149 @@ func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 		return false, nil
 	}
 
-	var needsUpdate bool
-	// If ConfigMap is missing OwnerReference, add it back.
-	if !metav1.IsControlledBy(&configMap, bundle) {
-		configMap.OwnerReferences = append(configMap.OwnerReferences, *metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle")))
+	// Never adopt a ConfigMap which is the target of another Bundle, since
+	// both Bundles would then overwrite each other's data.
+	if owner := otherBundleController(&configMap, bundle); len(owner) > 0 {
+		return false, fmt.Errorf("configmap %s/%s is already the target of Bundle %q", namespace.Name, name, owner)
+	}
+
+	// If ConfigMap is missing OwnerReference, or the target labels or
+	// annotations are missing or were modified, apply them again.
+	needsUpdate := !metav1.IsControlledBy(&configMap, bundle) || !targetMetadataMatches(&configMap.ObjectMeta, target.Metadata)
+
+	for key, value := range data.binaryData {
+		if current, ok := configMap.BinaryData[key]; !ok || !bytes.Equal(current, value) {
+			needsUpdate = true
+		}
+	}
+
+	for key, value := range data.stringData {
+		if current, ok := configMap.Data[key]; !ok || current != value {
+			needsUpdate = true
+		}
+	}
+
+	// If PEM not present, or if an additional key or format is required and not present or
+	// doesn't match, or configmap PEM doesn't match, or the hash of the target data has changed.
+	// The hash changes if the target was last written with different data, for example with
+	// a different trust store password. Additional formats are encoded deterministically, so
+	// any difference means that the target was modified and needs to be repaired.
+	if cmdata, ok := configMap.Data[target.ConfigMap.Key]; !ok || cmdata != data.data || configMap.Annotations[BundleHashAnnotationKey] != data.hash {
 		needsUpdate = true
 	}
 
-	needsJKS := false
-	if target.AdditionalFormats != nil && target.AdditionalFormats.JKS != nil {
-		if _, ok := configMap.BinaryData[target.AdditionalFormats.JKS.Key]; !ok {
-			needsJKS = true
+	// Exit early if no update is needed
+	if !needsUpdate {
+		return false, nil
+	}
+
+	if err := b.applyConfigMapTarget(ctx, bundle, &configMap, name, namespace.Name, data); err != nil {
+		return true, fmt.Errorf("failed to update configmap %s/%s with bundle: %w", namespace, name, err)
+	}
+
+	log.V(2).Info("synced bundle to namespace")
+
+	return true, nil
+}
+
+// applyConfigMapTarget writes the given data to the named target ConfigMap
+// using server-side apply with the trust-manager field manager. Only the
+// fields which trust-manager owns are written, so that labels, annotations
+// and keys of the ConfigMap which are managed by others are left untouched.
+// Fields which were applied before, but are no longer part of the target,
+// are removed. current is the existing ConfigMap, or nil if it doesn't exist.
+func (b *bundle) applyConfigMapTarget(ctx context.Context, bundle *trustapi.Bundle, current client.Object, name, namespace string, data targetData) error {
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: targetObjectMeta(bundle, name, namespace, data.hash),
+		Data: map[string]string{
+			bundle.Spec.Target.ConfigMap.Key: data.data,
+		},
+	}
+
+	for key, value := range data.stringData {
+		configMap.Data[key] = value
+	}
+
+	if len(data.binaryData) > 0 {
+		configMap.BinaryData = data.binaryData
+	}
+
+	return b.applyTarget(ctx, current, configMap)
+}
+
+// syncSecretTarget syncs the given data to the target Secret in the given namespace.
+// The name of the Secret is the same as the Bundle.
+// Ensures the Secret is owned by the given Bundle, and the data is up to date.
+// Returns true if the Secret has been created or was updated.
+func (b *bundle) syncSecretTarget(ctx context.Context, log logr.Logger,
+	bundle *trustapi.Bundle,
+	namespaceSelector labels.Selector,
+	namespace *corev1.Namespace,
+	data targetData,
+) (bool, error) {
+	target := bundle.Spec.Target
+
+	if target.Secret == nil {
+		return false, errors.New("target not defined")
+	}
+
+	matchNamespace := namespaceSelector.Matches(util.NamespaceLabels(namespace))
+
+	var secret corev1.Secret
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: bundle.Name}, &secret)
+
+	// If the Secret doesn't exist yet, create it.
+	if apierrors.IsNotFound(err) {
+		// If the namespace doesn't match selector we do nothing since we don't
+		// want to create it, and it also doesn't exist.
+		if !matchNamespace {
+			log.V(4).Info("ignoring namespace as it doesn't match selector", "labels", namespace.Labels)
+			return false, nil
 		}
+
+		return true, b.applySecretTarget(ctx, bundle, nil, namespace.Name, data)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get secret %s/%s: %w", namespace.Name, bundle.Name, err)
 	}
 
-	// If PEM not present, or if JKS required and not present, or configmap PEM doesn't match
-	// Generated JKS is not deterministic - best we can do here is update if the pem cert has
-	// changed (hence not checking if JKS matches)
-	if cmdata, ok := configMap.Data[target.ConfigMap.Key]; !ok || needsJKS || cmdata != data {
-		if configMap.Data == nil {
-			configMap.Data = make(map[string]string)
+	// Here, the Secret exists, but the selector doesn't match the namespace.
+	if !matchNamespace {
+		// The Secret is owned by this controller- delete it.
+		if metav1.IsControlledBy(&secret, bundle) {
+			log.V(2).Info("deleting bundle from Namespace since namespaceSelector does not match")
+			return true, b.targetDirectClient.Delete(ctx, &secret)
 		}
+		// The Secret isn't owned by us, so we shouldn't delete it. Return that
+		// we did nothing.
+		b.recorder.Eventf(&secret, corev1.EventTypeWarning, "NotOwned", "Secret is not owned by trust.cert-manager.io so ignoring")
+		return false, nil
+	}
 
-		configMap.Data[target.ConfigMap.Key] = data
-		if binData != nil {
-			if configMap.BinaryData == nil {
-				configMap.BinaryData = make(map[string][]byte)
-			}
+	// If Secret is missing OwnerReference, or the target labels or
+	// annotations are missing or were modified, apply them again.
+	needsUpdate := !metav1.IsControlledBy(&secret, bundle) || !targetMetadataMatches(&secret.ObjectMeta, target.Metadata)
 
-			configMap.BinaryData[target.AdditionalFormats.JKS.Key] = *binData
+	for key, value := range data.binaryData {
+		if current, ok := secret.Data[key]; !ok || !bytes.Equal(current, value) {
+			needsUpdate = true
 		}
+	}
+
+	for key, value := range data.stringData {
+		if current, ok := secret.Data[key]; !ok || string(current) != value {
+			needsUpdate = true
+		}
+	}
 
+	// If PEM not present, or if an additional key or format is required and not present or
+	// doesn't match, or Secret PEM doesn't match, or the hash of the target data has changed.
+	// The hash changes if the target was last written with different data, for example with
+	// a different trust store password. Additional formats are encoded deterministically, so
+	// any difference means that the target was modified and needs to be repaired.
+	if secretData, ok := secret.Data[target.Secret.Key]; !ok || string(secretData) != data.data || secret.Annotations[BundleHashAnnotationKey] != data.hash {
 		needsUpdate = true
 	}
 
@@ -347,11 +1049,223 @@ func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 		return false, nil
 	}
 
-	if err := b.targetDirectClient.Update(ctx, &configMap); err != nil {
-		return true, fmt.Errorf("failed to update configmap %s/%s with bundle: %w", namespace, bundle.Name, err)
+	if err := b.applySecretTarget(ctx, bundle, &secret, namespace.Name, data); err != nil {
+		return true, fmt.Errorf("failed to update secret %s/%s with bundle: %w", namespace.Name, bundle.Name, err)
 	}
 
 	log.V(2).Info("synced bundle to namespace")
 
 	return true, nil
 }
+
+// applySecretTarget writes the given data to the target Secret using
+// server-side apply with the trust-manager field manager. Only the fields
+// which trust-manager owns are written, so that labels, annotations and keys
+// of the Secret which are managed by others are left untouched. current is
+// the existing Secret, or nil if it doesn't exist.
+func (b *bundle) applySecretTarget(ctx context.Context, bundle *trustapi.Bundle, current client.Object, namespace string, data targetData) error {
+	secret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: targetObjectMeta(bundle, bundle.Name, namespace, data.hash),
+		Data: map[string][]byte{
+			bundle.Spec.Target.Secret.Key: []byte(data.data),
+		},
+	}
+
+	for key, value := range data.stringData {
+		secret.Data[key] = []byte(value)
+	}
+
+	for key, value := range data.binaryData {
+		secret.Data[key] = value
+	}
+
+	return b.applyTarget(ctx, current, secret)
+}
+
+// applyTarget applies the given target object using server-side apply with
+// the trust-manager field manager. current is the existing target, or nil if
+// it doesn't exist yet. Before applying, fields of current which trust-manager
+// wrote with Create and Update requests, before it wrote targets with
+// server-side apply, are moved to the trust-manager apply field manager.
+// Otherwise server-side apply would never remove those fields once they are
+// no longer part of the target.
+func (b *bundle) applyTarget(ctx context.Context, current, apply client.Object) error {
+	if current != nil {
+		patch, err := csaupgrade.UpgradeManagedFieldsPatch(current, sets.New(FieldManager), FieldManager)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade managed fields of %s: %w", client.ObjectKeyFromObject(current), err)
+		}
+
+		if patch != nil {
+			if err := b.targetDirectClient.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+				return fmt.Errorf("failed to upgrade managed fields of %s: %w", client.ObjectKeyFromObject(current), err)
+			}
+		}
+	}
+
+	return b.targetDirectClient.Patch(ctx, apply, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// syncClusterTrustBundleTarget syncs the given data to the target
+// ClusterTrustBundle of the Bundle. Ensures the ClusterTrustBundle is owned by
+// the given Bundle, and the data is up to date.
+// Returns true if the ClusterTrustBundle has been created or was updated.
+func (b *bundle) syncClusterTrustBundleTarget(ctx context.Context, log logr.Logger,
+	bundle *trustapi.Bundle,
+	data targetData,
+) (bool, error) {
+	target := bundle.Spec.Target
+
+	if target.ClusterTrustBundle == nil {
+		return false, errors.New("target not defined")
+	}
+
+	name := clusterTrustBundleTargetName(bundle, &target)
+
+	var clusterTrustBundle certificatesv1alpha1.ClusterTrustBundle
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Name: name}, &clusterTrustBundle)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get ClusterTrustBundle %s: %w", name, err)
+	}
+
+	// If the ClusterTrustBundle exists, is owned by the Bundle and has the
+	// expected signer, metadata and data, there is nothing to do.
+	if err == nil &&
+		metav1.IsControlledBy(&clusterTrustBundle, bundle) &&
+		targetMetadataMatches(&clusterTrustBundle.ObjectMeta, target.Metadata) &&
+		clusterTrustBundle.Spec.SignerName == target.ClusterTrustBundle.SignerName &&
+		clusterTrustBundle.Spec.TrustBundle == data.data &&
+		clusterTrustBundle.Annotations[BundleHashAnnotationKey] == data.hash {
+		return false, nil
+	}
+
+	apply := &certificatesv1alpha1.ClusterTrustBundle{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterTrustBundle", APIVersion: certificatesv1alpha1.SchemeGroupVersion.String()},
+		ObjectMeta: targetObjectMeta(bundle, name, "", data.hash),
+		Spec: certificatesv1alpha1.ClusterTrustBundleSpec{
+			SignerName:  target.ClusterTrustBundle.SignerName,
+			TrustBundle: data.data,
+		},
+	}
+
+	var current client.Object
+	if err == nil {
+		current = &clusterTrustBundle
+	}
+
+	if err := b.applyTarget(ctx, current, apply); err != nil {
+		return true, fmt.Errorf("failed to apply ClusterTrustBundle %s with bundle: %w", name, err)
+	}
+
+	log.V(2).Info("synced bundle to ClusterTrustBundle", "name", name)
+
+	return true, nil
+}
+
+// clusterTrustBundleTargetName returns the name of the ClusterTrustBundle of
+// the given Bundle target. ClusterTrustBundles associated with a signer must
+// be prefixed with the signer name, with slashes replaced by colons.
+func clusterTrustBundleTargetName(bundle *trustapi.Bundle, target *trustapi.BundleTarget) string {
+	if target.ClusterTrustBundle == nil || len(target.ClusterTrustBundle.SignerName) == 0 {
+		return bundle.Name
+	}
+
+	return strings.ReplaceAll(target.ClusterTrustBundle.SignerName, "/", ":") + ":" + bundle.Name
+}
+
+// targetObjectMeta returns the object metadata which trust-manager applies to
+// a target of the given Bundle: the owner reference of the Bundle, the target
+// labels and annotations, and the hash of the target data, if not empty.
+func targetObjectMeta(bundle *trustapi.Bundle, name, namespace, hash string) metav1.ObjectMeta {
+	objectMeta := metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       namespace,
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
+	}
+
+	if metadata := bundle.Spec.Target.Metadata; metadata != nil {
+		for key, value := range metadata.Labels {
+			metav1.SetMetaDataLabel(&objectMeta, key, value)
+		}
+
+		for key, value := range metadata.Annotations {
+			metav1.SetMetaDataAnnotation(&objectMeta, key, value)
+		}
+	}
+
+	if len(hash) > 0 {
+		metav1.SetMetaDataAnnotation(&objectMeta, BundleHashAnnotationKey, hash)
+	}
+
+	return objectMeta
+}
+
+// configMapTargetName returns the name of the ConfigMap of the given Bundle
+// target, which defaults to the name of the Bundle.
+func configMapTargetName(bundle *trustapi.Bundle, target *trustapi.BundleTarget) string {
+	if target.ConfigMap != nil && len(target.ConfigMap.Name) > 0 {
+		return target.ConfigMap.Name
+	}
+
+	return bundle.Name
+}
+
+// otherBundleController returns the name of the Bundle controlling the given
+// object, if that is a Bundle other than the given one. Bundles are compared
+// by name, so that a recreated Bundle adopts the targets of its predecessor.
+func otherBundleController(obj metav1.Object, bundle *trustapi.Bundle) string {
+	owner := metav1.GetControllerOfNoCopy(obj)
+	if owner == nil || owner.Kind != "Bundle" || owner.Name == bundle.Name {
+		return ""
+	}
+
+	if gv, err := schema.ParseGroupVersion(owner.APIVersion); err != nil || gv.Group != trustapi.SchemeGroupVersion.Group {
+		return ""
+	}
+
+	return owner.Name
+}
+
+// targetKeys returns the keys which the given Bundle target writes in
+// addition to the key of the PEM-encoded trust bundle: the keys of the
+// additional formats and of the target keys.
+func targetKeys(target *trustapi.BundleTarget) []string {
+	var keys []string
+	if formats := target.AdditionalFormats; formats != nil {
+		if formats.JKS != nil {
+			keys = append(keys, formats.JKS.Key)
+		}
+		if formats.PKCS12 != nil {
+			keys = append(keys, formats.PKCS12.Key)
+		}
+	}
+
+	for _, key := range target.Keys {
+		keys = append(keys, key.Key)
+	}
+
+	return keys
+}
+
+// targetMetadataMatches returns true if the object has all labels and
+// annotations of the given target metadata.
+func targetMetadataMatches(objectMeta *metav1.ObjectMeta, metadata *trustapi.TargetMetadata) bool {
+	if metadata == nil {
+		return true
+	}
+
+	for key, value := range metadata.Labels {
+		if current, ok := objectMeta.Labels[key]; !ok || current != value {
+			return false
+		}
+	}
+
+	for key, value := range metadata.Annotations {
+		if current, ok := objectMeta.Annotations[key]; !ok || current != value {
+			return false
+		}
+	}
+
+	return true
+}
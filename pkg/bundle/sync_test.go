@@ -20,22 +20,32 @@ import (
 	"bytes"
 	"context"
 	"crypto/x509"
+	"encoding/asn1"
 	"encoding/pem"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"software.sslmate.com/src/go-pkcs12"
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/fspkg"
@@ -44,7 +54,111 @@ import (
 	jks "github.com/pavlo-v-chernykh/keystore-go/v4"
 )
 
-func Test_syncTarget(t *testing.T) {
+// serverSideApply emulates server-side apply of Bundle targets in the fake
+// client, which doesn't support apply patches. Applies are merged into the
+// live object by the structured merge field manager of the API server, so
+// that fields which the trust-manager field manager applied before, but no
+// longer applies, are removed. Applies must use the trust-manager field
+// manager and force ownership.
+var serverSideApply = interceptor.Funcs{
+	Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+		if patch.Type() != types.ApplyPatchType {
+			return c.Patch(ctx, obj, patch, opts...)
+		}
+
+		patchOptions := &client.PatchOptions{}
+		patchOptions.ApplyOptions(opts)
+		if patchOptions.FieldManager != FieldManager || patchOptions.Force == nil || !*patchOptions.Force {
+			return fmt.Errorf("expected apply with field manager %q and forced ownership, got %+v", FieldManager, patchOptions)
+		}
+
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		fieldManager, err := managedfields.NewDefaultCRDFieldManager(managedfields.NewDeducedTypeConverter(),
+			fakeObjectConvertor{}, fakeObjectDefaulter{}, fakeObjectCreater{}, gvk, gvk.GroupVersion(), "", nil)
+		if err != nil {
+			return err
+		}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(gvk)
+		err = c.Get(ctx, client.ObjectKeyFromObject(obj), live)
+		exists := err == nil
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		applied, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+
+		merged, err := fieldManager.Apply(live, &unstructured.Unstructured{Object: applied}, FieldManager, true)
+		if err != nil {
+			return err
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(merged.(*unstructured.Unstructured).Object, obj); err != nil {
+			return err
+		}
+
+		if exists {
+			return c.Update(ctx, obj)
+		}
+
+		return c.Create(ctx, obj)
+	},
+}
+
+// trustManagerManagedFields returns managed fields under which trust-manager
+// owns the given fields of an object, written with the given operation. The
+// fields are given in the FieldsV1 format, e.g. `{"f:data":{"f:key":{}}}`.
+func trustManagerManagedFields(operation metav1.ManagedFieldsOperationType, fields string) []metav1.ManagedFieldsEntry {
+	return []metav1.ManagedFieldsEntry{{
+		Manager:    FieldManager,
+		Operation:  operation,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}}
+}
+
+// fakeObjectConvertor, fakeObjectDefaulter and fakeObjectCreater allow the
+// structured merge field manager to be used on unstructured objects of a
+// single version.
+type fakeObjectConvertor struct{ runtime.ObjectConvertor }
+
+func (fakeObjectConvertor) ConvertToVersion(in runtime.Object, _ runtime.GroupVersioner) (runtime.Object, error) {
+	return in, nil
+}
+
+type fakeObjectDefaulter struct{}
+
+func (fakeObjectDefaulter) Default(runtime.Object) {}
+
+type fakeObjectCreater struct{}
+
+func (fakeObjectCreater) New(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	return object, nil
+}
+
+// failNamespaces returns the serverSideApply interceptor, additionally failing
+// every patch to an object in one of the given Namespaces.
+func failNamespaces(namespaces []string) interceptor.Funcs {
+	funcs := serverSideApply
+	funcs.Patch = func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+		for _, namespace := range namespaces {
+			if obj.GetNamespace() == namespace {
+				return fmt.Errorf("injected failure in namespace %q", namespace)
+			}
+		}
+		return serverSideApply.Patch(ctx, c, obj, patch, opts...)
+	}
+	return funcs
+}
+
+func Test_syncConfigMapTarget(t *testing.T) {
 	const (
 		bundleName = "test-bundle"
 		key        = "trust.pem"
@@ -56,12 +170,23 @@ func Test_syncTarget(t *testing.T) {
 		return labels.Everything()
 	}
 
+	annotations := map[string]string{BundleHashAnnotationKey: targetDataHash(data, nil, nil)}
+
+	jksData, err := encodeJKS(data, []byte(DefaultJKSPassword))
+	assert.NoError(t, err)
+
+	jksAnnotations := map[string]string{BundleHashAnnotationKey: targetDataHash(data, nil, map[string][]byte{jksKey: jksData})}
+
 	tests := map[string]struct {
 		object    runtime.Object
 		namespace corev1.Namespace
 		selector  func(t *testing.T) labels.Selector
 		// Add JKS to AdditionalFormats
 		withJKS bool
+		// Name of the target ConfigMap, defaults to the name of the Bundle.
+		targetName string
+		// Labels and annotations of the target ConfigMap.
+		metadata *trustapi.TargetMetadata
 		// Expect the configmap to exist at the end of the sync.
 		expExists bool
 		// Expect JKS to exist in the configmap at the end of the sync.
@@ -70,7 +195,74 @@ func Test_syncTarget(t *testing.T) {
 		// Expect the owner reference of the configmap to point to the bundle.
 		expOwnerReference bool
 		expNeedsUpdate    bool
+		// Expect the configmap to have these labels at the end of the sync.
+		expLabels map[string]string
 	}{
+		"if object doesn't exist with a custom name and metadata, expect update": {
+			object:            nil,
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			targetName:        "custom-name",
+			metadata:          &trustapi.TargetMetadata{Labels: map[string]string{"team": "a"}, Annotations: map[string]string{"owner": "team-a"}},
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+			expLabels:         map[string]string{"team": "a"},
+		},
+		"if object exists with data but modified labels, expect update": {
+			object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      bundleName,
+					Namespace: "test-namespace",
+					Labels:    map[string]string{"team": "b", "other": "label"},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:               "Bundle",
+							APIVersion:         "trust.cert-manager.io/v1alpha1",
+							Name:               bundleName,
+							Controller:         pointer.Bool(true),
+							BlockOwnerDeletion: pointer.Bool(true),
+						},
+					},
+					Annotations: annotations,
+				},
+				Data: map[string]string{key: data},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			metadata:          &trustapi.TargetMetadata{Labels: map[string]string{"team": "a"}},
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+			expLabels:         map[string]string{"team": "a", "other": "label"},
+		},
+		"if object exists with data and labels, expect no update": {
+			object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      bundleName,
+					Namespace: "test-namespace",
+					Labels:    map[string]string{"team": "a"},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:               "Bundle",
+							APIVersion:         "trust.cert-manager.io/v1alpha1",
+							Name:               bundleName,
+							Controller:         pointer.Bool(true),
+							BlockOwnerDeletion: pointer.Bool(true),
+						},
+					},
+					Annotations: annotations,
+				},
+				Data: map[string]string{key: data},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			metadata:          &trustapi.TargetMetadata{Labels: map[string]string{"team": "a"}},
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    false,
+			expLabels:         map[string]string{"team": "a"},
+		},
 		"if object doesn't exist, expect update": {
 			object:            nil,
 			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
@@ -232,8 +424,9 @@ func Test_syncTarget(t *testing.T) {
 		"if object exists with correct data, expect no update": {
 			object: &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      bundleName,
-					Namespace: "test-namespace",
+					Name:        bundleName,
+					Namespace:   "test-namespace",
+					Annotations: annotations,
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Kind:               "Bundle",
@@ -252,6 +445,84 @@ func Test_syncTarget(t *testing.T) {
 			expOwnerReference: true,
 			expNeedsUpdate:    false,
 		},
+		"if object exists with correct data and JKS, expect no update": {
+			object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        bundleName,
+					Namespace:   "test-namespace",
+					Annotations: jksAnnotations,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:               "Bundle",
+							APIVersion:         "trust.cert-manager.io/v1alpha1",
+							Name:               bundleName,
+							Controller:         pointer.Bool(true),
+							BlockOwnerDeletion: pointer.Bool(true),
+						},
+					},
+				},
+				Data:       map[string]string{key: data},
+				BinaryData: map[string][]byte{jksKey: jksData},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			withJKS:           true,
+			expExists:         true,
+			expJKS:            true,
+			expOwnerReference: true,
+			expNeedsUpdate:    false,
+		},
+		"if object exists with owner but modified JKS, expect update": {
+			object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        bundleName,
+					Namespace:   "test-namespace",
+					Annotations: jksAnnotations,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:               "Bundle",
+							APIVersion:         "trust.cert-manager.io/v1alpha1",
+							Name:               bundleName,
+							Controller:         pointer.Bool(true),
+							BlockOwnerDeletion: pointer.Bool(true),
+						},
+					},
+				},
+				Data:       map[string]string{key: data},
+				BinaryData: map[string][]byte{jksKey: []byte("modified")},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			withJKS:           true,
+			expExists:         true,
+			expJKS:            true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with correct data but an outdated hash, expect update": {
+			object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        bundleName,
+					Namespace:   "test-namespace",
+					Annotations: map[string]string{BundleHashAnnotationKey: "outdated"},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:               "Bundle",
+							APIVersion:         "trust.cert-manager.io/v1alpha1",
+							Name:               bundleName,
+							Controller:         pointer.Bool(true),
+							BlockOwnerDeletion: pointer.Bool(true),
+						},
+					},
+				},
+				Data: map[string]string{key: data},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
 		"if object exists without JKS, expect update": {
 			object: &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
@@ -280,8 +551,9 @@ func Test_syncTarget(t *testing.T) {
 		"if object exists with correct data and some extra data and owner, expect no update": {
 			object: &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      bundleName,
-					Namespace: "test-namespace",
+					Name:        bundleName,
+					Namespace:   "test-namespace",
+					Annotations: annotations,
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Kind:               "Bundle",
@@ -340,8 +612,9 @@ func Test_syncTarget(t *testing.T) {
 		"if object exists with correct data and labels match, expect no update": {
 			object: &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      bundleName,
-					Namespace: "test-namespace",
+					Name:        bundleName,
+					Namespace:   "test-namespace",
+					Annotations: annotations,
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Kind:               "Bundle",
@@ -423,7 +696,7 @@ func Test_syncTarget(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme).WithInterceptorFuncs(serverSideApply)
 			if test.object != nil {
 				clientBuilder.WithRuntimeObjects(test.object)
 			}
@@ -433,25 +706,45 @@ func Test_syncTarget(t *testing.T) {
 
 			b := &bundle{targetDirectClient: fakeclient, recorder: fakerecorder}
 
-			spec := trustapi.BundleSpec{Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: key}}}
+			spec := trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{Name: test.targetName, KeySelector: trustapi.KeySelector{Key: key}},
+				Metadata:  test.metadata,
+			}}
 			if test.withJKS {
-				spec.Target.AdditionalFormats = &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: jksKey}}
+				spec.Target.AdditionalFormats = &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: jksKey}}}
 			}
 
-			needsUpdate, err := b.syncTarget(context.TODO(), klogr.New(), &trustapi.Bundle{
+			bundle := &trustapi.Bundle{
 				ObjectMeta: metav1.ObjectMeta{Name: bundleName},
 				Spec:       spec,
-			}, test.selector(t), &test.namespace, data)
+			}
+
+			resolvedTarget, err := b.buildTargetData(context.TODO(), bundle, data)
+			assert.NoError(t, err)
+
+			needsUpdate, err := b.syncConfigMapTarget(context.TODO(), klogr.New(), bundle, test.selector(t), &test.namespace, resolvedTarget)
 			assert.NoError(t, err)
 
 			assert.Equalf(t, test.expNeedsUpdate, needsUpdate, "unexpected needsUpdate, exp=%t got=%t", test.expNeedsUpdate, needsUpdate)
 
+			targetName := bundleName
+			if len(test.targetName) > 0 {
+				targetName = test.targetName
+			}
+
 			var configMap corev1.ConfigMap
-			err = fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: test.namespace.Name, Name: bundleName}, &configMap)
+			err = fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: test.namespace.Name, Name: targetName}, &configMap)
 			assert.Equalf(t, test.expExists, !apierrors.IsNotFound(err), "unexpected is not found: %v", err)
 
 			if test.expExists {
 				assert.Equalf(t, data, configMap.Data[key], "unexpected data on ConfigMap: exp=%s:%s got=%v", key, data, configMap.Data)
+				assert.Equal(t, test.expLabels, configMap.Labels)
+
+				if test.metadata != nil {
+					for key, value := range test.metadata.Annotations {
+						assert.Equal(t, value, configMap.Annotations[key])
+					}
+				}
 
 				expectedOwnerReference := metav1.OwnerReference{
 					Kind:               "Bundle",
@@ -500,57 +793,451 @@ func Test_syncTarget(t *testing.T) {
 	}
 }
 
-func Test_buildSourceBundle(t *testing.T) {
-	tests := map[string]struct {
-		bundle           *trustapi.Bundle
-		objects          []runtime.Object
-		expData          string
-		expError         bool
-		expNotFoundError bool
-	}{
-		"if no sources defined, should return an error": {
-			bundle:           &trustapi.Bundle{},
-			objects:          []runtime.Object{},
-			expData:          "",
-			expError:         true,
-			expNotFoundError: false,
-		},
-		"if single InLine source defined with newlines, should trim and return": {
-			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
-				{InLine: pointer.String(dummy.TestCertificate1 + "\n" + dummy.TestCertificate2 + "\n\n")},
-			}}},
-			objects:          []runtime.Object{},
-			expData:          dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
-			expError:         false,
-			expNotFoundError: false,
-		},
-		"if single DefaultPackage source defined, should return": {
-			bundle:           &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{{UseDefaultCAs: pointer.Bool(true)}}}},
-			objects:          []runtime.Object{},
-			expData:          dummy.JoinCerts(dummy.TestCertificate5),
-			expError:         false,
-			expNotFoundError: false,
+func Test_syncConfigMapTargetServerSideApply(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "trust.pem"
+		data       = dummy.TestCertificate1
+	)
+
+	trustBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+		Spec:       trustapi.BundleSpec{Target: trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: key}}}},
+	}
+
+	// The ConfigMap is shared with another controller, which manages its own
+	// key and annotation.
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        bundleName,
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{"reloader.stakater.com/match": "true"},
 		},
-		"if single ConfigMap source which doesn't exist, return notFoundError": {
-			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
-				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "configmap", KeySelector: trustapi.KeySelector{Key: "key"}}},
-			}}},
-			objects:          []runtime.Object{},
-			expData:          "",
-			expError:         true,
-			expNotFoundError: true,
+		Data: map[string]string{key: "outdated data", "other-key": "other data"},
+	}
+
+	var applied []client.Object
+	fakeclient := fakeclient.NewClientBuilder().
+		WithScheme(trustapi.GlobalScheme).
+		WithObjects(existing).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				applied = append(applied, obj.DeepCopyObject().(client.Object))
+				return serverSideApply.Patch(ctx, c, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	b := &bundle{targetDirectClient: fakeclient, recorder: record.NewFakeRecorder(1)}
+
+	resolvedTarget, err := b.buildTargetData(context.TODO(), trustBundle, data)
+	assert.NoError(t, err)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+	needsUpdate, err := b.syncConfigMapTarget(context.TODO(), klogr.New(), trustBundle, labels.Everything(), namespace, resolvedTarget)
+	assert.NoError(t, err)
+	assert.True(t, needsUpdate)
+
+	// Only the fields owned by trust-manager must be part of the apply.
+	if assert.Len(t, applied, 1) {
+		appliedConfigMap := applied[0].(*corev1.ConfigMap)
+		assert.Equal(t, map[string]string{key: data}, appliedConfigMap.Data)
+		assert.Equal(t, map[string]string{BundleHashAnnotationKey: resolvedTarget.hash}, appliedConfigMap.Annotations)
+		assert.Empty(t, appliedConfigMap.ResourceVersion)
+	}
+
+	var configMap corev1.ConfigMap
+	assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(existing), &configMap))
+	assert.Equal(t, map[string]string{key: data, "other-key": "other data"}, configMap.Data)
+	assert.Equal(t, "true", configMap.Annotations["reloader.stakater.com/match"])
+	assert.True(t, metav1.IsControlledBy(&configMap, trustBundle))
+}
+
+func Test_syncConfigMapTargetOwnedByOtherBundle(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "trust.pem"
+		data       = dummy.TestCertificate1
+	)
+
+	otherOwnerReference := metav1.OwnerReference{
+		Kind:               "Bundle",
+		APIVersion:         "trust.cert-manager.io/v1alpha1",
+		Name:               "other-bundle",
+		UID:                "other-uid",
+		Controller:         pointer.Bool(true),
+		BlockOwnerDeletion: pointer.Bool(true),
+	}
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "shared",
+			Namespace:       "test-namespace",
+			OwnerReferences: []metav1.OwnerReference{otherOwnerReference},
 		},
-		"if single ConfigMap source whose key doesn't exist, return notFoundError": {
-			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
-				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "configmap", KeySelector: trustapi.KeySelector{Key: "key"}}},
-			}}},
-			objects:          []runtime.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "configmap"}}},
-			expData:          "",
-			expError:         true,
-			expNotFoundError: true,
+		Data: map[string]string{"other.pem": "other data"},
+	}
+
+	fakeclient := fakeclient.NewClientBuilder().
+		WithScheme(trustapi.GlobalScheme).
+		WithObjects(existing).
+		WithInterceptorFuncs(serverSideApply).
+		Build()
+
+	b := &bundle{targetDirectClient: fakeclient, recorder: record.NewFakeRecorder(1)}
+
+	trustBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: bundleName, UID: "test-uid"},
+		Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{
+			Name:        "shared",
+			KeySelector: trustapi.KeySelector{Key: key},
+		}}},
+	}
+
+	resolvedTarget, err := b.buildTargetData(context.TODO(), trustBundle, data)
+	assert.NoError(t, err)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+	needsUpdate, err := b.syncConfigMapTarget(context.TODO(), klogr.New(), trustBundle, labels.Everything(), namespace, resolvedTarget)
+	assert.EqualError(t, err, `configmap test-namespace/shared is already the target of Bundle "other-bundle"`)
+	assert.False(t, needsUpdate)
+
+	// When the Bundle stops targeting the ConfigMap, it must not be deleted or
+	// modified as it belongs to the other Bundle.
+	trustBundle.Status.Target = trustBundle.Spec.Target.DeepCopy()
+	trustBundle.Spec.Target.ConfigMap.Name = ""
+	assert.NoError(t, b.deleteOldConfigMapTarget(context.TODO(), klogr.New(), trustBundle, namespace.Name))
+
+	var configMap corev1.ConfigMap
+	assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(existing), &configMap))
+	assert.Equal(t, []metav1.OwnerReference{otherOwnerReference}, configMap.OwnerReferences)
+	assert.Equal(t, map[string]string{"other.pem": "other data"}, configMap.Data)
+	assert.Equal(t, existing.ResourceVersion, configMap.ResourceVersion)
+}
+
+func Test_syncSecretTarget(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "trust.pem"
+		jksKey     = "trust.jks"
+		data       = dummy.TestCertificate1
+	)
+
+	labelEverything := func(*testing.T) labels.Selector {
+		return labels.Everything()
+	}
+
+	annotations := map[string]string{BundleHashAnnotationKey: targetDataHash(data, nil, nil)}
+
+	jksData, err := encodeJKS(data, []byte(DefaultJKSPassword))
+	assert.NoError(t, err)
+
+	jksAnnotations := map[string]string{BundleHashAnnotationKey: targetDataHash(data, nil, map[string][]byte{jksKey: jksData})}
+
+	ownerReferences := []metav1.OwnerReference{
+		{
+			Kind:               "Bundle",
+			APIVersion:         "trust.cert-manager.io/v1alpha1",
+			Name:               bundleName,
+			Controller:         pointer.Bool(true),
+			BlockOwnerDeletion: pointer.Bool(true),
 		},
-		"if single ConfigMap source, return data": {
-			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+	}
+
+	tests := map[string]struct {
+		object    runtime.Object
+		namespace corev1.Namespace
+		selector  func(t *testing.T) labels.Selector
+		// Add JKS to AdditionalFormats
+		withJKS bool
+		// Expect the secret to exist at the end of the sync.
+		expExists bool
+		// Expect JKS to exist in the secret at the end of the sync.
+		expJKS   bool
+		expEvent string
+		// Expect the owner reference of the secret to point to the bundle.
+		expOwnerReference bool
+		expNeedsUpdate    bool
+	}{
+		"if object doesn't exist, expect update": {
+			object:            nil,
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object doesn't exist with JKS, expect update": {
+			object:            nil,
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			withJKS:           true,
+			expExists:         true,
+			expJKS:            true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with data but no owner, expect update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace"},
+				Data:       map[string][]byte{key: []byte(data)},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with owner but wrong data, expect update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace", OwnerReferences: ownerReferences},
+				Data:       map[string][]byte{key: []byte("wrong data")},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with owner but without JKS, expect update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace", OwnerReferences: ownerReferences},
+				Data:       map[string][]byte{key: []byte(data)},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			withJKS:           true,
+			expExists:         true,
+			expJKS:            true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with correct data and JKS, expect no update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace", OwnerReferences: ownerReferences, Annotations: jksAnnotations},
+				Data:       map[string][]byte{key: []byte(data), jksKey: jksData},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			withJKS:           true,
+			expExists:         true,
+			expJKS:            true,
+			expOwnerReference: true,
+			expNeedsUpdate:    false,
+		},
+		"if object exists with owner but modified JKS, expect update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace", OwnerReferences: ownerReferences, Annotations: jksAnnotations},
+				Data:       map[string][]byte{key: []byte(data), jksKey: []byte("modified")},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			withJKS:           true,
+			expExists:         true,
+			expJKS:            true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with correct data but an outdated hash, expect update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            bundleName,
+					Namespace:       "test-namespace",
+					OwnerReferences: ownerReferences,
+					Annotations:     map[string]string{BundleHashAnnotationKey: "outdated"},
+				},
+				Data: map[string][]byte{key: []byte(data)},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    true,
+		},
+		"if object exists with correct data and some extra data and owner, expect no update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace", OwnerReferences: ownerReferences, Annotations: annotations},
+				Data:       map[string][]byte{key: []byte(data), "another-key": []byte("another-data")},
+			},
+			namespace:         corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			selector:          labelEverything,
+			expExists:         true,
+			expOwnerReference: true,
+			expNeedsUpdate:    false,
+		},
+		"if object doesn't exist and labels don't match, don't expect update": {
+			object: nil,
+			namespace: corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-namespace",
+				Labels: map[string]string{"bar": "foo"},
+			}},
+			selector: func(t *testing.T) labels.Selector {
+				req, err := labels.NewRequirement("foo", selection.Equals, []string{"bar"})
+				assert.NoError(t, err)
+				return labels.NewSelector().Add(*req)
+			},
+			expExists:         false,
+			expOwnerReference: true,
+			expNeedsUpdate:    false,
+		},
+		"if object exists with correct data but labels don't match, expect deletion": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace", OwnerReferences: ownerReferences},
+				Data:       map[string][]byte{key: []byte(data)},
+			},
+			namespace: corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-namespace",
+				Labels: map[string]string{"bar": "foo"},
+			}},
+			selector: func(t *testing.T) labels.Selector {
+				req, err := labels.NewRequirement("foo", selection.Equals, []string{"bar"})
+				assert.NoError(t, err)
+				return labels.NewSelector().Add(*req)
+			},
+			expExists:         false,
+			expOwnerReference: false,
+			expNeedsUpdate:    true,
+		},
+		"if object exists and labels don't match, but controller doesn't have ownership, expect no update": {
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: "test-namespace"},
+				Data:       map[string][]byte{key: []byte(data)},
+			},
+			namespace: corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-namespace",
+				Labels: map[string]string{"bar": "foo"},
+			}},
+			selector: func(t *testing.T) labels.Selector {
+				req, err := labels.NewRequirement("foo", selection.Equals, []string{"bar"})
+				assert.NoError(t, err)
+				return labels.NewSelector().Add(*req)
+			},
+			expExists:         true,
+			expOwnerReference: false,
+			expNeedsUpdate:    false,
+			expEvent:          "Warning NotOwned Secret is not owned by trust.cert-manager.io so ignoring",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme).WithInterceptorFuncs(serverSideApply)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+
+			fakeclient := clientBuilder.Build()
+			fakerecorder := record.NewFakeRecorder(1)
+
+			b := &bundle{targetDirectClient: fakeclient, recorder: fakerecorder}
+
+			spec := trustapi.BundleSpec{Target: trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: key}}}
+			if test.withJKS {
+				spec.Target.AdditionalFormats = &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: jksKey}}}
+			}
+
+			bundle := &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+				Spec:       spec,
+			}
+
+			resolvedTarget, err := b.buildTargetData(context.TODO(), bundle, data)
+			assert.NoError(t, err)
+
+			needsUpdate, err := b.syncSecretTarget(context.TODO(), klogr.New(), bundle, test.selector(t), &test.namespace, resolvedTarget)
+			assert.NoError(t, err)
+
+			assert.Equalf(t, test.expNeedsUpdate, needsUpdate, "unexpected needsUpdate, exp=%t got=%t", test.expNeedsUpdate, needsUpdate)
+
+			var secret corev1.Secret
+			err = fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: test.namespace.Name, Name: bundleName}, &secret)
+			assert.Equalf(t, test.expExists, !apierrors.IsNotFound(err), "unexpected is not found: %v", err)
+
+			if test.expExists {
+				assert.Equalf(t, data, string(secret.Data[key]), "unexpected data on Secret: exp=%s:%s got=%v", key, data, secret.Data)
+
+				if test.expOwnerReference {
+					assert.Equalf(t, ownerReferences[0], secret.OwnerReferences[0], "unexpected owner reference on Secret: got=%v", secret.OwnerReferences)
+				} else {
+					assert.NotContains(t, secret.OwnerReferences, ownerReferences[0])
+				}
+
+				jksData, jksExists := secret.Data[jksKey]
+				assert.Equal(t, test.expJKS, jksExists)
+
+				if test.expJKS {
+					ks := jks.New()
+					err := ks.Load(bytes.NewReader(jksData), []byte(DefaultJKSPassword))
+					assert.Nil(t, err)
+
+					entryNames := ks.Aliases()
+					assert.Len(t, entryNames, 1)
+					assert.True(t, ks.IsTrustedCertificateEntry(entryNames[0]))
+				}
+			}
+
+			var event string
+			select {
+			case event = <-fakerecorder.Events:
+			default:
+			}
+			assert.Equal(t, test.expEvent, event)
+		})
+	}
+}
+
+func Test_buildSourceBundle(t *testing.T) {
+	tests := map[string]struct {
+		bundle           *trustapi.Bundle
+		objects          []runtime.Object
+		expData          string
+		expError         bool
+		expNotFoundError bool
+		expCycleError    bool
+	}{
+		"if no sources defined, should return an error": {
+			bundle:           &trustapi.Bundle{},
+			objects:          []runtime.Object{},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: false,
+		},
+		"if single InLine source defined with newlines, should trim and return": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{InLine: pointer.String(dummy.TestCertificate1 + "\n" + dummy.TestCertificate2 + "\n\n")},
+			}}},
+			objects:          []runtime.Object{},
+			expData:          dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if single DefaultPackage source defined, should return": {
+			bundle:           &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{{UseDefaultCAs: pointer.Bool(true)}}}},
+			objects:          []runtime.Object{},
+			expData:          dummy.JoinCerts(dummy.TestCertificate5),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if single ConfigMap source which doesn't exist, return notFoundError": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "configmap", KeySelector: trustapi.KeySelector{Key: "key"}}},
+			}}},
+			objects:          []runtime.Object{},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if single ConfigMap source whose key doesn't exist, return notFoundError": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "configmap", KeySelector: trustapi.KeySelector{Key: "key"}}},
+			}}},
+			objects:          []runtime.Object{&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "configmap"}}},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if single ConfigMap source, return data": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
 				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "configmap", KeySelector: trustapi.KeySelector{Key: "key"}}},
 			}}},
 			objects: []runtime.Object{&corev1.ConfigMap{
@@ -574,6 +1261,150 @@ func Test_buildSourceBundle(t *testing.T) {
 			expError:         false,
 			expNotFoundError: false,
 		},
+		"if ConfigMap selector source, return concatenated data of all matching ConfigMaps ordered by name": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ConfigMap: &trustapi.SourceObjectKeySelector{
+					Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+					KeySelector: trustapi.KeySelector{Key: "key"},
+				}},
+			}}},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "configmap-b", Labels: map[string]string{"trust": "true"}},
+					Data:       map[string]string{"key": dummy.TestCertificate2},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "configmap-a", Labels: map[string]string{"trust": "true"}},
+					Data:       map[string]string{"key": dummy.TestCertificate1},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "configmap-c", Labels: map[string]string{"trust": "false"}},
+					Data:       map[string]string{"key": dummy.TestCertificate3},
+				},
+			},
+			expData:          dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if ConfigMap selector source which matches nothing, return notFoundError": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ConfigMap: &trustapi.SourceObjectKeySelector{
+					Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+					KeySelector: trustapi.KeySelector{Key: "key"},
+				}},
+			}}},
+			objects: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "configmap", Labels: map[string]string{"trust": "false"}},
+				Data:       map[string]string{"key": dummy.TestCertificate1},
+			}},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if Secret selector source, return concatenated data of all matching Secrets ordered by name": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{Secret: &trustapi.SourceObjectKeySelector{
+					Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+					KeySelector: trustapi.KeySelector{Key: "key"},
+				}},
+			}}},
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret-b", Labels: map[string]string{"trust": "true"}},
+					Data:       map[string][]byte{"key": []byte(dummy.TestCertificate3)},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret-a", Labels: map[string]string{"trust": "true"}},
+					Data:       map[string][]byte{"key": []byte(dummy.TestCertificate2)},
+				},
+			},
+			expData:          dummy.JoinCerts(dummy.TestCertificate2, dummy.TestCertificate3),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if Secret selector source where one matching Secret is missing the key, return notFoundError": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{Secret: &trustapi.SourceObjectKeySelector{
+					Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+					KeySelector: trustapi.KeySelector{Key: "key"},
+				}},
+			}}},
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret-a", Labels: map[string]string{"trust": "true"}},
+					Data:       map[string][]byte{"key": []byte(dummy.TestCertificate2)},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret-b", Labels: map[string]string{"trust": "true"}},
+					Data:       map[string][]byte{"other-key": []byte(dummy.TestCertificate3)},
+				},
+			},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if single ClusterTrustBundle source, return data": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ClusterTrustBundle: &trustapi.ClusterTrustBundleSource{Name: "example.com:foo:ca"}},
+			}}},
+			objects: []runtime.Object{&certificatesv1alpha1.ClusterTrustBundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "example.com:foo:ca"},
+				Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{SignerName: "example.com/foo", TrustBundle: dummy.TestCertificate1},
+			}},
+			expData:          dummy.JoinCerts(dummy.TestCertificate1),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if single ClusterTrustBundle source which doesn't exist, return notFoundError": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ClusterTrustBundle: &trustapi.ClusterTrustBundleSource{Name: "ca"}},
+			}}},
+			objects:          []runtime.Object{},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if ClusterTrustBundle signer source, return concatenated data of all ClusterTrustBundles of the signer matching the selector ordered by name": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ClusterTrustBundle: &trustapi.ClusterTrustBundleSource{
+					SignerName: "example.com/foo",
+					Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+				}},
+			}}},
+			objects: []runtime.Object{
+				&certificatesv1alpha1.ClusterTrustBundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "example.com:foo:b", Labels: map[string]string{"trust": "true"}},
+					Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{SignerName: "example.com/foo", TrustBundle: dummy.TestCertificate2},
+				},
+				&certificatesv1alpha1.ClusterTrustBundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "example.com:foo:a", Labels: map[string]string{"trust": "true"}},
+					Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{SignerName: "example.com/foo", TrustBundle: dummy.TestCertificate1},
+				},
+				&certificatesv1alpha1.ClusterTrustBundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "example.com:foo:c", Labels: map[string]string{"trust": "false"}},
+					Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{SignerName: "example.com/foo", TrustBundle: dummy.TestCertificate3},
+				},
+				&certificatesv1alpha1.ClusterTrustBundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "example.com:bar:a", Labels: map[string]string{"trust": "true"}},
+					Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{SignerName: "example.com/bar", TrustBundle: dummy.TestCertificate4},
+				},
+			},
+			expData:          dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if ClusterTrustBundle signer source which matches nothing, return notFoundError": {
+			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+				{ClusterTrustBundle: &trustapi.ClusterTrustBundleSource{SignerName: "example.com/foo"}},
+			}}},
+			objects: []runtime.Object{&certificatesv1alpha1.ClusterTrustBundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "example.com:bar:a"},
+				Spec:       certificatesv1alpha1.ClusterTrustBundleSpec{SignerName: "example.com/bar", TrustBundle: dummy.TestCertificate1},
+			}},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
 		"if single Secret source exists which doesn't exist, should return not found error": {
 			bundle: &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
 				{Secret: &trustapi.SourceObjectKeySelector{Name: "secret", KeySelector: trustapi.KeySelector{Key: "key"}}},
@@ -667,6 +1498,86 @@ func Test_buildSourceBundle(t *testing.T) {
 			expError:         true,
 			expNotFoundError: true,
 		},
+		"if Bundle source exists, return its resolved data": {
+			bundle: &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "a"},
+				Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+					{InLine: pointer.String(dummy.TestCertificate1)},
+					{Bundle: &trustapi.BundleReference{Name: "b"}},
+				}},
+			},
+			objects: []runtime.Object{
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "b"},
+					Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+						{InLine: pointer.String(dummy.TestCertificate2)},
+						{Bundle: &trustapi.BundleReference{Name: "c"}},
+					}},
+				},
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "c"},
+					Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+						{InLine: pointer.String(dummy.TestCertificate3)},
+					}},
+				},
+			},
+			expData:          dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3),
+			expError:         false,
+			expNotFoundError: false,
+		},
+		"if Bundle source doesn't exist, return not found error": {
+			bundle: &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "a"},
+				Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+					{Bundle: &trustapi.BundleReference{Name: "b"}},
+				}},
+			},
+			objects:          []runtime.Object{},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if Bundle source references the Bundle itself, return cycle error": {
+			bundle: &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "a"},
+				Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+					{InLine: pointer.String(dummy.TestCertificate1)},
+					{Bundle: &trustapi.BundleReference{Name: "a"}},
+				}},
+			},
+			objects:          []runtime.Object{},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: false,
+			expCycleError:    true,
+		},
+		"if Bundle sources reference each other in a cycle, return cycle error": {
+			bundle: &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "a"},
+				Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+					{Bundle: &trustapi.BundleReference{Name: "b"}},
+				}},
+			},
+			objects: []runtime.Object{
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "b"},
+					Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+						{Bundle: &trustapi.BundleReference{Name: "c"}},
+					}},
+				},
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "c"},
+					Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+						{InLine: pointer.String(dummy.TestCertificate1)},
+						{Bundle: &trustapi.BundleReference{Name: "b"}},
+					}},
+				},
+			},
+			expData:          "",
+			expError:         true,
+			expNotFoundError: false,
+			expCycleError:    true,
+		},
 	}
 
 	for name, test := range tests {
@@ -694,6 +1605,9 @@ func Test_buildSourceBundle(t *testing.T) {
 			if errors.As(err, &notFoundError{}) != test.expNotFoundError {
 				t.Errorf("unexpected notFoundError, exp=%t got=%v", test.expNotFoundError, err)
 			}
+			if errors.As(err, &cycleError{}) != test.expCycleError {
+				t.Errorf("unexpected cycleError, exp=%t got=%v", test.expCycleError, err)
+			}
 
 			if resolvedBundle.data != test.expData {
 				t.Errorf("unexpected data, exp=%q got=%q", test.expData, resolvedBundle.data)
@@ -733,7 +1647,321 @@ func Test_encodeJKSAliases(t *testing.T) {
 	}
 }
 
-func Test_jksAlias(t *testing.T) {
+func Test_encodeJKSDeterministic(t *testing.T) {
+	bundle := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+
+	jksFile, err := encodeJKS(bundle, []byte(DefaultJKSPassword))
+	if err != nil {
+		t.Fatalf("didn't expect an error but got: %s", err)
+	}
+
+	// Encoding the same bundle again must give the same result, so that
+	// targets can be compared with the expected JKS file.
+	jksFileAgain, err := encodeJKS(bundle, []byte(DefaultJKSPassword))
+	if err != nil {
+		t.Fatalf("didn't expect an error but got: %s", err)
+	}
+
+	if !bytes.Equal(jksFile, jksFileAgain) {
+		t.Error("expected JKS encoding to be deterministic")
+	}
+
+	jksFileOtherPassword, err := encodeJKS(bundle, []byte("other-password"))
+	if err != nil {
+		t.Fatalf("didn't expect an error but got: %s", err)
+	}
+
+	if bytes.Equal(jksFile, jksFileOtherPassword) {
+		t.Error("expected JKS encoding to change with the password")
+	}
+}
+
+func Test_encodePKCS12(t *testing.T) {
+	bundle := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+
+	for name, password := range map[string]string{
+		"without a password": "",
+		"with a password":    "my-password",
+	} {
+		t.Run(name, func(t *testing.T) {
+			pfxData, err := encodePKCS12(bundle, password)
+			if err != nil {
+				t.Fatalf("didn't expect an error but got: %s", err)
+			}
+
+			certs, err := pkcs12.DecodeTrustStore(pfxData, password)
+			if err != nil {
+				t.Fatalf("failed to parse generated PKCS12 file: %s", err)
+			}
+
+			var pemCerts []string
+			for _, cert := range certs {
+				pemCerts = append(pemCerts, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+			}
+
+			if got := strings.Join(pemCerts, ""); got != bundle {
+				t.Errorf("expected certificates in PKCS12 file to match bundle in order, exp=%q got=%q", bundle, got)
+			}
+
+			// Encoding the same bundle again must give the same result, so that
+			// targets are only updated if the bundle has changed.
+			pfxDataAgain, err := encodePKCS12(bundle, password)
+			if err != nil {
+				t.Fatalf("didn't expect an error but got: %s", err)
+			}
+
+			if !bytes.Equal(pfxData, pfxDataAgain) {
+				t.Error("expected PKCS12 encoding to be deterministic")
+			}
+		})
+	}
+}
+
+func Test_encodePKCS12SaltsDifferByPassword(t *testing.T) {
+	bundle := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+
+	// macSalt returns the salt of the MAC of the given PKCS12 file.
+	macSalt := func(pfxData []byte) []byte {
+		var pfx struct {
+			Version  int
+			AuthSafe asn1.RawValue
+			MacData  struct {
+				Mac        asn1.RawValue
+				MacSalt    []byte
+				Iterations int
+			}
+		}
+		if _, err := asn1.Unmarshal(pfxData, &pfx); err != nil {
+			t.Fatalf("failed to parse generated PKCS12 file: %s", err)
+		}
+		return pfx.MacData.MacSalt
+	}
+
+	pfxData1, err := encodePKCS12(bundle, "password-1")
+	if err != nil {
+		t.Fatalf("didn't expect an error but got: %s", err)
+	}
+
+	pfxData2, err := encodePKCS12(bundle, "password-2")
+	if err != nil {
+		t.Fatalf("didn't expect an error but got: %s", err)
+	}
+
+	salt1, salt2 := macSalt(pfxData1), macSalt(pfxData2)
+	if len(salt1) == 0 || bytes.Equal(salt1, salt2) {
+		t.Errorf("expected trust stores with different passwords to use different salts, got %x and %x", salt1, salt2)
+	}
+}
+
+func Test_encodeDER(t *testing.T) {
+	bundle := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+
+	certs, err := x509.ParseCertificates(encodeDER(bundle))
+	if err != nil {
+		t.Fatalf("failed to parse generated DER data: %s", err)
+	}
+
+	assertCertificatesMatchBundle(t, bundle, certs)
+}
+
+func Test_encodePKCS7(t *testing.T) {
+	bundle := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+
+	pkcs7Data, err := encodePKCS7(bundle)
+	if err != nil {
+		t.Fatalf("didn't expect an error but got: %s", err)
+	}
+
+	var contentInfo pkcs7ContentInfo
+	if rest, err := asn1.Unmarshal(pkcs7Data, &contentInfo); err != nil || len(rest) > 0 {
+		t.Fatalf("failed to parse generated PKCS7 content info: %v", err)
+	}
+
+	assert.True(t, contentInfo.ContentType.Equal(oidPKCS7SignedData), "unexpected content type %s", contentInfo.ContentType)
+
+	var signedData pkcs7SignedData
+	if rest, err := asn1.Unmarshal(contentInfo.Content.Bytes, &signedData); err != nil || len(rest) > 0 {
+		t.Fatalf("failed to parse generated PKCS7 signed data: %v", err)
+	}
+
+	assert.Equal(t, 1, signedData.Version)
+	assert.Empty(t, signedData.DigestAlgorithms.Bytes)
+	assert.Empty(t, signedData.SignerInfos.Bytes)
+
+	certs, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse certificates in generated PKCS7 file: %s", err)
+	}
+
+	assertCertificatesMatchBundle(t, bundle, certs)
+}
+
+// assertCertificatesMatchBundle asserts that the given certificates are the
+// certificates of the given PEM-encoded bundle, in order.
+func assertCertificatesMatchBundle(t *testing.T, bundle string, certs []*x509.Certificate) {
+	var pemCerts []string
+	for _, cert := range certs {
+		pemCerts = append(pemCerts, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+	}
+
+	if got := strings.Join(pemCerts, ""); got != bundle {
+		t.Errorf("expected certificates to match bundle in order, exp=%q got=%q", bundle, got)
+	}
+}
+
+func Test_buildTargetData(t *testing.T) {
+	const (
+		trustNamespace = "trust-namespace"
+		jksKey         = "trust.jks"
+		pkcs12Key      = "trust.p12"
+	)
+
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "passwords", Namespace: trustNamespace},
+		Data: map[string][]byte{
+			"jks":    []byte("jks-password"),
+			"pkcs12": []byte("pkcs12-password"),
+		},
+	}
+
+	secretRef := func(name, key string) *trustapi.SecretKeySelector {
+		return &trustapi.SecretKeySelector{Name: name, KeySelector: trustapi.KeySelector{Key: key}}
+	}
+
+	tests := map[string]struct {
+		formats *trustapi.AdditionalFormats
+		keys    []trustapi.TargetKey
+		objects []runtime.Object
+
+		expJKSPassword    string
+		expPKCS12Password string
+		expStringKeys     []string
+		expBinaryKeys     []string
+		expNotFoundError  bool
+	}{
+		"if no additional formats are requested, return no binary data": {},
+		"if JKS without password is requested, use the default password": {
+			formats:        &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: jksKey}}},
+			expJKSPassword: DefaultJKSPassword,
+		},
+		"if JKS with inline password is requested, use the inline password": {
+			formats:        &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: jksKey}, Password: pointer.String("inline-password")}},
+			expJKSPassword: "inline-password",
+		},
+		"if JKS and PKCS12 with password Secret references are requested, use the passwords from the Secret": {
+			formats: &trustapi.AdditionalFormats{
+				JKS:    &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: jksKey}, PasswordSecretRef: secretRef("passwords", "jks")},
+				PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: pkcs12Key}, PasswordSecretRef: secretRef("passwords", "pkcs12")},
+			},
+			objects:           []runtime.Object{passwordSecret},
+			expJKSPassword:    "jks-password",
+			expPKCS12Password: "pkcs12-password",
+		},
+		"if target keys are requested, encode each key in its format": {
+			keys: []trustapi.TargetKey{
+				{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+				{KeySelector: trustapi.KeySelector{Key: "ca-certificates.crt"}, Format: trustapi.TargetFormatPEM},
+				{KeySelector: trustapi.KeySelector{Key: jksKey}, Format: trustapi.TargetFormatJKS, PasswordSecretRef: secretRef("passwords", "jks")},
+				{KeySelector: trustapi.KeySelector{Key: pkcs12Key}, Format: trustapi.TargetFormatPKCS12},
+				{KeySelector: trustapi.KeySelector{Key: "trust.der"}, Format: trustapi.TargetFormatDER},
+				{KeySelector: trustapi.KeySelector{Key: "trust.p7b"}, Format: trustapi.TargetFormatPKCS7},
+			},
+			objects:        []runtime.Object{passwordSecret},
+			expJKSPassword: "jks-password",
+			expStringKeys:  []string{"ca.crt", "ca-certificates.crt"},
+			expBinaryKeys:  []string{jksKey, pkcs12Key, "trust.der", "trust.p7b"},
+		},
+		"if the password Secret of a target key doesn't exist, return notFoundError": {
+			keys:             []trustapi.TargetKey{{KeySelector: trustapi.KeySelector{Key: jksKey}, Format: trustapi.TargetFormatJKS, PasswordSecretRef: secretRef("does-not-exist", "jks")}},
+			expNotFoundError: true,
+		},
+		"if the password Secret doesn't exist, return notFoundError": {
+			formats:          &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: jksKey}, PasswordSecretRef: secretRef("passwords", "jks")}},
+			expNotFoundError: true,
+		},
+		"if the password Secret doesn't have the key, return notFoundError": {
+			formats:          &trustapi.AdditionalFormats{PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: pkcs12Key}, PasswordSecretRef: secretRef("passwords", "does-not-exist")}},
+			objects:          []runtime.Object{passwordSecret},
+			expNotFoundError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects(test.objects...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{sourceLister: fakeclient, Options: Options{Namespace: trustNamespace}}
+
+			bundle := &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"},
+				Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+					ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "trust.pem"}},
+					AdditionalFormats: test.formats,
+					Keys:              test.keys,
+				}},
+			}
+
+			resolvedTarget, err := b.buildTargetData(context.TODO(), bundle, data)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), "unexpected error: %v", err)
+			if test.expNotFoundError {
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, data, resolvedTarget.data)
+			assert.Equal(t, targetDataHash(data, resolvedTarget.stringData, resolvedTarget.binaryData), resolvedTarget.hash)
+
+			if len(test.expJKSPassword) > 0 {
+				ks := jks.New()
+				assert.NoError(t, ks.Load(bytes.NewReader(resolvedTarget.binaryData[jksKey]), []byte(test.expJKSPassword)))
+				assert.Len(t, ks.Aliases(), 2)
+			} else {
+				assert.NotContains(t, resolvedTarget.binaryData, jksKey)
+			}
+
+			if len(test.expPKCS12Password) > 0 {
+				certs, err := pkcs12.DecodeTrustStore(resolvedTarget.binaryData[pkcs12Key], test.expPKCS12Password)
+				assert.NoError(t, err)
+				assert.Len(t, certs, 2)
+			} else if test.keys == nil {
+				assert.NotContains(t, resolvedTarget.binaryData, pkcs12Key)
+			}
+
+			assert.Len(t, resolvedTarget.stringData, len(test.expStringKeys))
+			for _, key := range test.expStringKeys {
+				assert.Equal(t, data, resolvedTarget.stringData[key])
+			}
+
+			for _, key := range test.expBinaryKeys {
+				assert.NotEmpty(t, resolvedTarget.binaryData[key], "expected binary data at key %q", key)
+			}
+		})
+	}
+}
+
+func Test_targetDataHash(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1)
+
+	jksChangeit, err := encodeJKS(data, []byte(DefaultJKSPassword))
+	assert.NoError(t, err)
+
+	jksOther, err := encodeJKS(data, []byte("other-password"))
+	assert.NoError(t, err)
+
+	hash := targetDataHash(data, nil, map[string][]byte{"trust.jks": jksChangeit})
+
+	assert.Equal(t, hash, targetDataHash(data, nil, map[string][]byte{"trust.jks": jksChangeit}), "expected hash to be stable")
+	assert.NotEqual(t, hash, targetDataHash(data, nil, map[string][]byte{"trust.jks": jksOther}), "expected hash to change when the password changes")
+	assert.NotEqual(t, hash, targetDataHash(data, nil, map[string][]byte{"other.jks": jksChangeit}), "expected hash to change when the key changes")
+	assert.NotEqual(t, hash, targetDataHash(data, nil, nil), "expected hash to change when binary data is removed")
+}
+
+func Test_certAlias(t *testing.T) {
 	// We might not ever rely on aliases being stable, but this test seeks
 	// to enforce stability for now. It'll be easy to remove.
 
@@ -750,7 +1978,7 @@ func Test_jksAlias(t *testing.T) {
 		t.Fatalf("Dummy certificate TestCertificate1 couldn't be parsed: %s", err)
 	}
 
-	alias := jksAlias(cert.Raw, cert.Subject.String())
+	alias := certAlias(cert.Raw, cert.Subject.String())
 
 	expectedAlias := "548b988f|CN=cmct-test-root,O=cert-manager"
 
@@ -0,0 +1,360 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+const (
+	// defaultURLRefreshInterval is how often a URL source is fetched if the
+	// source doesn't define a refresh interval.
+	defaultURLRefreshInterval = time.Hour
+
+	// urlRetryInterval is the longest time to wait before fetching a URL
+	// source again after a failed fetch.
+	urlRetryInterval = time.Minute
+
+	// urlFetchTimeout is the timeout for fetching the data of a URL source.
+	// Fetching blocks the reconcile of the Bundle, so this bounds how long a
+	// slow or unresponsive server can delay syncing Bundles.
+	urlFetchTimeout = 30 * time.Second
+
+	// maxURLSourceSize is the maximum number of bytes read from a URL source.
+	maxURLSourceSize = 10 << 20
+
+	// urlCacheKey is the key of the cache ConfigMap data holding the last
+	// successfully fetched data of a URL source.
+	urlCacheKey = "bundle.pem"
+
+	// urlCacheLabel is set on all cache ConfigMaps of URL sources.
+	urlCacheLabel = "trust.cert-manager.io/url-cache"
+
+	// urlCacheAddressAnnotation holds the URL which a cache ConfigMap caches.
+	urlCacheAddressAnnotation = "trust.cert-manager.io/url"
+
+	// urlCacheFetchedAtAnnotation holds the time at which the data in a cache
+	// ConfigMap was fetched, in RFC 3339 format.
+	urlCacheFetchedAtAnnotation = "trust.cert-manager.io/last-fetched"
+)
+
+// newURLClient returns the HTTP client used to fetch URL sources, which sends
+// requests with the given transport, or the default transport if nil.
+func newURLClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       urlFetchTimeout,
+		CheckRedirect: checkURLRedirect,
+	}
+}
+
+// checkURLRedirect refuses redirects to anything but https URLs, so that the
+// https-only requirement of URL sources can't be bypassed by a redirect. The
+// default limit of 10 redirects is kept.
+func checkURLRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing to follow redirect to non-https URL %s", req.URL.Redacted())
+	}
+
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	return nil
+}
+
+// urlCacheName returns the name of the ConfigMap in the trust Namespace which
+// caches the data fetched from the given URL. The cache is shared between all
+// Bundles which use the same URL as a source.
+func urlCacheName(address string) string {
+	hash := sha256.Sum256([]byte(address))
+	return "trust-manager-url-" + hex.EncodeToString(hash[:])[:32]
+}
+
+// urlBundle returns the data published at the URL of the given source. Data
+// is served from the cache in the trust Namespace until the refresh interval
+// has passed, after which the URL is fetched again. If fetching fails, the
+// last successfully fetched data is returned instead and the source is
+// recorded as degraded in resolvedBundle.
+// Fetching happens synchronously in Reconcile, at most once per refresh
+// interval, and takes at most urlFetchTimeout.
+func (b *bundle) urlBundle(ctx context.Context, ref *trustapi.URLSource, resolvedBundle *bundleData) (string, error) {
+	refreshInterval := defaultURLRefreshInterval
+	if ref.RefreshInterval != nil {
+		refreshInterval = ref.RefreshInterval.Duration
+	}
+
+	retryInterval := urlRetryInterval
+	if refreshInterval < retryInterval {
+		retryInterval = refreshInterval
+	}
+
+	var cache *corev1.ConfigMap
+	var existingCache corev1.ConfigMap
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: urlCacheName(ref.Address)}, &existingCache)
+	switch {
+	case err == nil:
+		cache = &existingCache
+	case !apierrors.IsNotFound(err):
+		return "", fmt.Errorf("failed to get cache ConfigMap for URL %s: %w", ref.Address, err)
+	}
+
+	cachedData, fetchedAt, cacheOK := cachedURLData(cache, ref)
+
+	// Fetches which didn't change the data are not written to the cache, so
+	// the last fetch may be more recent than the cache.
+	if lastFetch := b.lastURLFetch(ref.Address); cacheOK && lastFetch.After(fetchedAt) {
+		fetchedAt = lastFetch
+	}
+
+	now := b.clock.Now()
+	if cacheOK && now.Before(fetchedAt.Add(refreshInterval)) {
+		resolvedBundle.refreshAt(fetchedAt.Add(refreshInterval))
+		return cachedData, nil
+	}
+
+	data, err := b.fetchURL(ctx, ref)
+	if err != nil {
+		resolvedBundle.refreshAt(now.Add(retryInterval))
+
+		if !cacheOK {
+			return "", fmt.Errorf("failed to fetch %s and no cached data is available: %w", ref.Address, err)
+		}
+
+		resolvedBundle.degradedSources = append(resolvedBundle.degradedSources,
+			fmt.Sprintf("failed to fetch %s, using data last fetched at %s: %s", ref.Address, fetchedAt.Format(time.RFC3339), err))
+
+		return cachedData, nil
+	}
+
+	if cacheOK && data == cachedData {
+		b.recordURLFetch(ref.Address, now)
+	} else if err := b.updateURLCache(ctx, cache, ref.Address, data, now); err != nil {
+		return "", err
+	}
+
+	resolvedBundle.refreshAt(now.Add(refreshInterval))
+
+	return data, nil
+}
+
+// cachedURLData returns the cached data of the given URL source, and the time
+// at which it was fetched. Returns false if there is no usable cached data,
+// for example because the data doesn't match the pinned digest.
+func cachedURLData(cache *corev1.ConfigMap, ref *trustapi.URLSource) (string, time.Time, bool) {
+	if cache == nil || cache.Annotations[urlCacheAddressAnnotation] != ref.Address {
+		return "", time.Time{}, false
+	}
+
+	data, ok := cache.Data[urlCacheKey]
+	if !ok {
+		return "", time.Time{}, false
+	}
+
+	fetchedAt, err := time.Parse(time.RFC3339, cache.Annotations[urlCacheFetchedAtAnnotation])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	if len(ref.SHA256) > 0 && !digestMatches([]byte(data), ref.SHA256) {
+		return "", time.Time{}, false
+	}
+
+	return data, fetchedAt, true
+}
+
+// fetchURL fetches the data published at the URL of the given source. The
+// data must match the pinned digest, if set, and must be a valid PEM bundle.
+func (b *bundle) fetchURL(ctx context.Context, ref *trustapi.URLSource) (string, error) {
+	address, err := url.Parse(ref.Address)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	if address.Scheme != "https" {
+		return "", fmt.Errorf("only https URLs are supported, got %q", address.Scheme)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, urlFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.Address, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxURLSourceSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if len(body) > maxURLSourceSize {
+		return "", fmt.Errorf("response body is larger than %d bytes", maxURLSourceSize)
+	}
+
+	if len(ref.SHA256) > 0 && !digestMatches(body, ref.SHA256) {
+		return "", fmt.Errorf("SHA-256 digest of fetched data does not match pinned digest %s", ref.SHA256)
+	}
+
+	if _, err := util.ValidateAndSanitizePEMBundle(body); err != nil {
+		return "", fmt.Errorf("invalid PEM data fetched: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// updateURLCache stores the data fetched from the given URL in the cache
+// ConfigMap in the trust Namespace. If cache is nil, the ConfigMap is created.
+func (b *bundle) updateURLCache(ctx context.Context, cache *corev1.ConfigMap, address, data string, fetchedAt time.Time) error {
+	if cache == nil {
+		cache = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      urlCacheName(address),
+				Namespace: b.Namespace,
+			},
+		}
+	} else {
+		cache = cache.DeepCopy()
+	}
+
+	if cache.Labels == nil {
+		cache.Labels = make(map[string]string)
+	}
+	cache.Labels[urlCacheLabel] = "true"
+
+	if cache.Annotations == nil {
+		cache.Annotations = make(map[string]string)
+	}
+	cache.Annotations[urlCacheAddressAnnotation] = address
+	cache.Annotations[urlCacheFetchedAtAnnotation] = fetchedAt.UTC().Format(time.RFC3339)
+
+	cache.Data = map[string]string{urlCacheKey: data}
+
+	if len(cache.ResourceVersion) == 0 {
+		if err := b.targetDirectClient.Create(ctx, cache); err != nil {
+			return fmt.Errorf("failed to create cache ConfigMap for URL %s: %w", address, err)
+		}
+
+		return nil
+	}
+
+	if err := b.targetDirectClient.Update(ctx, cache); err != nil {
+		return fmt.Errorf("failed to update cache ConfigMap for URL %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// lastURLFetch returns the time at which the given URL was last fetched
+// without changing the cached data, or the zero time if it wasn't.
+func (b *bundle) lastURLFetch(address string) time.Time {
+	b.urlFetchesLock.Lock()
+	defer b.urlFetchesLock.Unlock()
+
+	return b.urlFetches[address]
+}
+
+// recordURLFetch records that the given URL was fetched at the given time
+// without changing the cached data.
+func (b *bundle) recordURLFetch(address string, fetchedAt time.Time) {
+	b.urlFetchesLock.Lock()
+	defer b.urlFetchesLock.Unlock()
+
+	if b.urlFetches == nil {
+		b.urlFetches = make(map[string]time.Time)
+	}
+	b.urlFetches[address] = fetchedAt
+}
+
+// pruneURLCaches deletes the cache ConfigMaps of URLs which are no longer a
+// source of any Bundle. Cache ConfigMaps are shared between Bundles, so they
+// can't be owned by a single Bundle and garbage collected by Kubernetes.
+func (b *bundle) pruneURLCaches(ctx context.Context) error {
+	var cacheList corev1.ConfigMapList
+	if err := b.sourceLister.List(ctx, &cacheList, client.InNamespace(b.Namespace), client.HasLabels{urlCacheLabel}); err != nil {
+		return fmt.Errorf("failed to list URL cache ConfigMaps: %w", err)
+	}
+
+	if len(cacheList.Items) == 0 {
+		return nil
+	}
+
+	var bundleList trustapi.BundleList
+	if err := b.sourceLister.List(ctx, &bundleList); err != nil {
+		return fmt.Errorf("failed to list Bundles: %w", err)
+	}
+
+	inUse := make(map[string]bool)
+	for _, bundle := range bundleList.Items {
+		for _, source := range bundle.Spec.Sources {
+			if source.URL != nil {
+				inUse[urlCacheName(source.URL.Address)] = true
+			}
+		}
+	}
+
+	for i := range cacheList.Items {
+		cache := &cacheList.Items[i]
+		if inUse[cache.Name] {
+			continue
+		}
+
+		if err := b.targetDirectClient.Delete(ctx, cache); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete unused cache ConfigMap %s: %w", cache.Name, err)
+		}
+
+		b.urlFetchesLock.Lock()
+		delete(b.urlFetches, cache.Annotations[urlCacheAddressAnnotation])
+		b.urlFetchesLock.Unlock()
+	}
+
+	return nil
+}
+
+// digestMatches returns true if the SHA-256 digest of data matches the given
+// hex-encoded digest.
+func digestMatches(data []byte, digest string) bool {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]) == strings.ToLower(digest)
+}
@@ -0,0 +1,443 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_urlBundle(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	var (
+		fixedTime    = time.Date(2023, 01, 01, 12, 0, 0, 0, time.UTC)
+		servedData   = dummy.JoinCerts(dummy.TestCertificate1)
+		cachedData   = dummy.JoinCerts(dummy.TestCertificate2)
+		servedDigest = sha256.Sum256([]byte(servedData))
+		servedSHA256 = hex.EncodeToString(servedDigest[:])
+		cachedDigest = sha256.Sum256([]byte(cachedData))
+		cachedSHA256 = hex.EncodeToString(cachedDigest[:])
+		anHourAgo    = fixedTime.Add(-time.Hour)
+	)
+
+	cacheWithData := func(address, data string, fetchedAt time.Time) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      urlCacheName(address),
+				Namespace: trustNamespace,
+				Labels:    map[string]string{urlCacheLabel: "true"},
+				Annotations: map[string]string{
+					urlCacheAddressAnnotation:   address,
+					urlCacheFetchedAtAnnotation: fetchedAt.Format(time.RFC3339),
+				},
+			},
+			Data: map[string]string{urlCacheKey: data},
+		}
+	}
+
+	tests := map[string]struct {
+		// serverStatus is the status code returned by the test server.
+		serverStatus int
+		// plainAddress uses an http:// address instead of the test server.
+		plainAddress    bool
+		refreshInterval *metav1.Duration
+		sha256          string
+		// cache returns the cache ConfigMap for the given address, if any.
+		cache func(address string) runtime.Object
+
+		expData        string
+		expError       bool
+		expFetches     int32
+		expDegraded    bool
+		expNextRefresh time.Time
+		expCachedData  string
+	}{
+		"if no cache exists, fetch data and create the cache": {
+			serverStatus:   http.StatusOK,
+			expData:        servedData,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(defaultURLRefreshInterval),
+			expCachedData:  servedData,
+		},
+		"if the cache is still fresh, return cached data without fetching": {
+			serverStatus: http.StatusOK,
+			cache: func(address string) runtime.Object {
+				return cacheWithData(address, cachedData, fixedTime.Add(-time.Minute))
+			},
+			expData:        cachedData,
+			expFetches:     0,
+			expNextRefresh: fixedTime.Add(-time.Minute).Add(defaultURLRefreshInterval),
+			expCachedData:  cachedData,
+		},
+		"if the cache is stale, fetch data and update the cache": {
+			serverStatus: http.StatusOK,
+			cache: func(address string) runtime.Object {
+				return cacheWithData(address, cachedData, anHourAgo)
+			},
+			expData:        servedData,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(defaultURLRefreshInterval),
+			expCachedData:  servedData,
+		},
+		"if a custom refresh interval has passed, fetch data": {
+			serverStatus:    http.StatusOK,
+			refreshInterval: &metav1.Duration{Duration: 5 * time.Minute},
+			cache: func(address string) runtime.Object {
+				return cacheWithData(address, cachedData, fixedTime.Add(-10*time.Minute))
+			},
+			expData:        servedData,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(5 * time.Minute),
+			expCachedData:  servedData,
+		},
+		"if fetching fails and the cache is stale, return cached data and mark as degraded": {
+			serverStatus: http.StatusInternalServerError,
+			cache: func(address string) runtime.Object {
+				return cacheWithData(address, cachedData, anHourAgo)
+			},
+			expData:        cachedData,
+			expFetches:     1,
+			expDegraded:    true,
+			expNextRefresh: fixedTime.Add(urlRetryInterval),
+			expCachedData:  cachedData,
+		},
+		"if fetching fails and no cache exists, return an error": {
+			serverStatus:   http.StatusInternalServerError,
+			expError:       true,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(urlRetryInterval),
+		},
+		"if the fetched data matches the pinned digest, return data": {
+			serverStatus:   http.StatusOK,
+			sha256:         servedSHA256,
+			expData:        servedData,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(defaultURLRefreshInterval),
+			expCachedData:  servedData,
+		},
+		"if the fetched data doesn't match the pinned digest and no cache exists, return an error": {
+			serverStatus:   http.StatusOK,
+			sha256:         cachedSHA256,
+			expError:       true,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(urlRetryInterval),
+		},
+		"if the fetched data doesn't match the pinned digest, return cached data matching the digest": {
+			serverStatus: http.StatusOK,
+			sha256:       cachedSHA256,
+			cache: func(address string) runtime.Object {
+				return cacheWithData(address, cachedData, anHourAgo)
+			},
+			expData:        cachedData,
+			expFetches:     1,
+			expDegraded:    true,
+			expNextRefresh: fixedTime.Add(urlRetryInterval),
+			expCachedData:  cachedData,
+		},
+		"if fresh cached data doesn't match the pinned digest, fetch data": {
+			serverStatus: http.StatusOK,
+			sha256:       servedSHA256,
+			cache: func(address string) runtime.Object {
+				return cacheWithData(address, cachedData, fixedTime.Add(-time.Minute))
+			},
+			expData:        servedData,
+			expFetches:     1,
+			expNextRefresh: fixedTime.Add(defaultURLRefreshInterval),
+			expCachedData:  servedData,
+		},
+		"if the URL is not https, return an error": {
+			serverStatus:   http.StatusOK,
+			plainAddress:   true,
+			expError:       true,
+			expFetches:     0,
+			expNextRefresh: fixedTime.Add(urlRetryInterval),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var fetches atomic.Int32
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fetches.Add(1)
+				w.WriteHeader(test.serverStatus)
+				_, _ = w.Write([]byte(servedData))
+			}))
+			defer server.Close()
+
+			address := server.URL + "/bundle.pem"
+			if test.plainAddress {
+				address = "http://example.com/bundle.pem"
+			}
+
+			var objects []runtime.Object
+			if test.cache != nil {
+				objects = append(objects, test.cache(address))
+			}
+
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects(objects...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{
+				targetDirectClient: fakeclient,
+				sourceLister:       fakeclient,
+				httpClient:         newURLClient(server.Client().Transport),
+				clock:              fakeclock.NewFakeClock(fixedTime),
+				Options:            Options{Namespace: trustNamespace},
+			}
+
+			var resolvedBundle bundleData
+			data, err := b.urlBundle(context.TODO(), &trustapi.URLSource{
+				Address:         address,
+				RefreshInterval: test.refreshInterval,
+				SHA256:          test.sha256,
+			}, &resolvedBundle)
+
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expData, data)
+			assert.Equal(t, test.expFetches, fetches.Load())
+			assert.Equal(t, test.expDegraded, len(resolvedBundle.degradedSources) > 0)
+			assert.Equal(t, test.expNextRefresh, resolvedBundle.nextRefresh)
+
+			var cache corev1.ConfigMap
+			err = fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: trustNamespace, Name: urlCacheName(address)}, &cache)
+			if len(test.expCachedData) == 0 {
+				assert.Error(t, err, "expected no cache ConfigMap to exist")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expCachedData, cache.Data[urlCacheKey])
+			assert.Equal(t, address, cache.Annotations[urlCacheAddressAnnotation])
+		})
+	}
+}
+
+func Test_urlBundleUnchangedData(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	var (
+		fixedTime  = time.Date(2023, 01, 01, 12, 0, 0, 0, time.UTC)
+		servedData = dummy.JoinCerts(dummy.TestCertificate1)
+		anHourAgo  = fixedTime.Add(-time.Hour)
+	)
+
+	var fetches atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_, _ = w.Write([]byte(servedData))
+	}))
+	defer server.Close()
+
+	address := server.URL + "/bundle.pem"
+
+	fakeclient := fakeclient.NewClientBuilder().
+		WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      urlCacheName(address),
+				Namespace: trustNamespace,
+				Labels:    map[string]string{urlCacheLabel: "true"},
+				Annotations: map[string]string{
+					urlCacheAddressAnnotation:   address,
+					urlCacheFetchedAtAnnotation: anHourAgo.Format(time.RFC3339),
+				},
+			},
+			Data: map[string]string{urlCacheKey: servedData},
+		}).
+		WithScheme(trustapi.GlobalScheme).
+		Build()
+
+	var before corev1.ConfigMap
+	assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: trustNamespace, Name: urlCacheName(address)}, &before))
+
+	b := &bundle{
+		targetDirectClient: fakeclient,
+		sourceLister:       fakeclient,
+		httpClient:         newURLClient(server.Client().Transport),
+		clock:              fakeclock.NewFakeClock(fixedTime),
+		Options:            Options{Namespace: trustNamespace},
+	}
+
+	// The stale cache is fetched again, but the cache ConfigMap isn't
+	// written as the data didn't change.
+	var resolvedBundle bundleData
+	data, err := b.urlBundle(context.TODO(), &trustapi.URLSource{Address: address}, &resolvedBundle)
+	assert.NoError(t, err)
+	assert.Equal(t, servedData, data)
+	assert.Equal(t, int32(1), fetches.Load())
+	assert.Equal(t, fixedTime.Add(defaultURLRefreshInterval), resolvedBundle.nextRefresh)
+
+	var after corev1.ConfigMap
+	assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(&before), &after))
+	assert.Equal(t, before.ResourceVersion, after.ResourceVersion)
+
+	// The fetch is still remembered, so the URL isn't fetched again until
+	// the refresh interval has passed.
+	resolvedBundle = bundleData{}
+	data, err = b.urlBundle(context.TODO(), &trustapi.URLSource{Address: address}, &resolvedBundle)
+	assert.NoError(t, err)
+	assert.Equal(t, servedData, data)
+	assert.Equal(t, int32(1), fetches.Load())
+	assert.Equal(t, fixedTime.Add(defaultURLRefreshInterval), resolvedBundle.nextRefresh)
+}
+
+func Test_pruneURLCaches(t *testing.T) {
+	const (
+		trustNamespace = "trust-namespace"
+		usedAddress    = "https://example.com/used.pem"
+		unusedAddress  = "https://example.com/unused.pem"
+	)
+
+	cache := func(address string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        urlCacheName(address),
+				Namespace:   trustNamespace,
+				Labels:      map[string]string{urlCacheLabel: "true"},
+				Annotations: map[string]string{urlCacheAddressAnnotation: address},
+			},
+			Data: map[string]string{urlCacheKey: dummy.TestCertificate1},
+		}
+	}
+
+	// Not a cache ConfigMap, even though it has the name of a cache.
+	unlabelled := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: urlCacheName("https://example.com/other.pem"), Namespace: trustNamespace},
+	}
+
+	fakeclient := fakeclient.NewClientBuilder().
+		WithObjects(
+			cache(usedAddress),
+			cache(unusedAddress),
+			unlabelled,
+			&trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"},
+				Spec: trustapi.BundleSpec{Sources: []trustapi.BundleSource{
+					{InLine: pointer.String(dummy.TestCertificate2)},
+					{URL: &trustapi.URLSource{Address: usedAddress}},
+				}},
+			},
+		).
+		WithScheme(trustapi.GlobalScheme).
+		Build()
+
+	b := &bundle{
+		targetDirectClient: fakeclient,
+		sourceLister:       fakeclient,
+		Options:            Options{Namespace: trustNamespace},
+	}
+	b.recordURLFetch(unusedAddress, time.Now())
+
+	assert.NoError(t, b.pruneURLCaches(context.TODO()))
+
+	var configMapList corev1.ConfigMapList
+	assert.NoError(t, fakeclient.List(context.TODO(), &configMapList))
+
+	var names []string
+	for _, configMap := range configMapList.Items {
+		names = append(names, configMap.Name)
+	}
+	assert.ElementsMatch(t, []string{urlCacheName(usedAddress), unlabelled.Name}, names)
+	assert.True(t, b.lastURLFetch(unusedAddress).IsZero())
+}
+
+func Test_urlBundleRedirect(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	servedData := dummy.JoinCerts(dummy.TestCertificate1)
+
+	var plainFetches atomic.Int32
+	plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plainFetches.Add(1)
+		_, _ = w.Write([]byte(servedData))
+	}))
+	defer plainServer.Close()
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/to-https":
+			http.Redirect(w, r, server.URL+"/bundle.pem", http.StatusFound)
+		case "/to-http":
+			http.Redirect(w, r, plainServer.URL+"/bundle.pem", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(servedData))
+		}
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		path     string
+		expError bool
+	}{
+		"if the URL redirects to https, follow it": {
+			path: "/to-https",
+		},
+		"if the URL redirects to plain http, return an error": {
+			path:     "/to-http",
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{
+				targetDirectClient: fakeclient,
+				sourceLister:       fakeclient,
+				httpClient:         newURLClient(server.Client().Transport),
+				clock:              fakeclock.NewFakeClock(time.Now()),
+				Options:            Options{Namespace: trustNamespace},
+			}
+
+			var resolvedBundle bundleData
+			data, err := b.urlBundle(context.TODO(), &trustapi.URLSource{Address: server.URL + test.path}, &resolvedBundle)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+
+			if test.expError {
+				assert.Empty(t, data)
+			} else {
+				assert.Equal(t, servedData, data)
+			}
+
+			assert.Equal(t, int32(0), plainFetches.Load(), "expected the plain http server never to be requested")
+		})
+	}
+}
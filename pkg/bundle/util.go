@@ -70,6 +70,24 @@ func (b *bundle) setBundleCondition(bundle *trustapi.Bundle, condition trustapi.
 	bundle.Status.Conditions = append(updatedConditions, condition)
 }
 
+// removeBundleCondition removes any condition of the given type from the
+// bundle. Returns true if a condition was removed.
+func removeBundleCondition(bundle *trustapi.Bundle, conditionType trustapi.BundleConditionType) bool {
+	var updatedConditions []trustapi.BundleCondition
+	for _, existingCondition := range bundle.Status.Conditions {
+		if existingCondition.Type != conditionType {
+			updatedConditions = append(updatedConditions, existingCondition)
+		}
+	}
+
+	if len(updatedConditions) == len(bundle.Status.Conditions) {
+		return false
+	}
+
+	bundle.Status.Conditions = updatedConditions
+	return true
+}
+
 // setBundleStatusDefaultCAVersion ensures that the given Bundle's Status correctly
 // reflects the defaultCAVersion represented by requiredID.
 // Returns true if the bundle status needs updating.
@@ -220,6 +220,51 @@ func Test_setBundleCondition(t *testing.T) {
 	}
 }
 
+func Test_removeBundleCondition(t *testing.T) {
+	tests := map[string]struct {
+		existingConditions []trustapi.BundleCondition
+		conditionType      trustapi.BundleConditionType
+		expectedConditions []trustapi.BundleCondition
+		expectUpdate       bool
+	}{
+		"no existing conditions should not update": {
+			existingConditions: nil,
+			conditionType:      trustapi.BundleConditionDegraded,
+			expectedConditions: nil,
+			expectUpdate:       false,
+		},
+		"no condition of the given type should not update": {
+			existingConditions: []trustapi.BundleCondition{{Type: trustapi.BundleConditionSynced, Status: corev1.ConditionTrue}},
+			conditionType:      trustapi.BundleConditionDegraded,
+			expectedConditions: []trustapi.BundleCondition{{Type: trustapi.BundleConditionSynced, Status: corev1.ConditionTrue}},
+			expectUpdate:       false,
+		},
+		"condition of the given type should be removed": {
+			existingConditions: []trustapi.BundleCondition{
+				{Type: trustapi.BundleConditionSynced, Status: corev1.ConditionTrue},
+				{Type: trustapi.BundleConditionDegraded, Status: corev1.ConditionTrue},
+			},
+			conditionType:      trustapi.BundleConditionDegraded,
+			expectedConditions: []trustapi.BundleCondition{{Type: trustapi.BundleConditionSynced, Status: corev1.ConditionTrue}},
+			expectUpdate:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bundle := &trustapi.Bundle{Status: trustapi.BundleStatus{Conditions: test.existingConditions}}
+
+			if updated := removeBundleCondition(bundle, test.conditionType); updated != test.expectUpdate {
+				t.Errorf("expected update=%v got=%v", test.expectUpdate, updated)
+			}
+
+			if !apiequality.Semantic.DeepEqual(bundle.Status.Conditions, test.expectedConditions) {
+				t.Errorf("unexpected conditions, exp=%v got=%v", test.expectedConditions, bundle.Status.Conditions)
+			}
+		})
+	}
+}
+
 func Test_setBundleStatusDefaultCAVersion(t *testing.T) {
 	var (
 		fixedTime  = time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)
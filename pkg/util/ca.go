@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// EffectiveCARequirement returns the CA requirement which the certificates of
+// a Bundle with the given spec must meet. ClusterTrustBundles may only hold
+// CA certificates, so Bundles with a ClusterTrustBundle target require CA
+// certificates even if their own requirement is weaker.
+func EffectiveCARequirement(spec *trustapi.BundleSpec) trustapi.CARequirement {
+	requirement := spec.CARequirement
+	if spec.Target.ClusterTrustBundle != nil && (len(requirement) == 0 || requirement == trustapi.CARequirementAny) {
+		return trustapi.CARequirementCA
+	}
+
+	return requirement
+}
+
+// ValidateCACertificate returns an error if the given certificate is not a
+// CA certificate with valid basic constraints. If selfSignedOnly is true, an
+// error is also returned if the certificate is not a self-signed root
+// certificate, so that intermediate CAs can't be used as trust anchors.
+func ValidateCACertificate(cert *x509.Certificate, selfSignedOnly bool) error {
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return fmt.Errorf("certificate %q is not a CA certificate", cert.Subject.String())
+	}
+
+	if !selfSignedOnly {
+		return nil
+	}
+
+	// CheckSignature is used rather than CheckSignatureFrom since the latter
+	// rejects SHA-1 signatures, which are still common on older roots.
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) ||
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) != nil {
+		return fmt.Errorf("certificate %q is not a self-signed root certificate", cert.Subject.String())
+	}
+
+	return nil
+}
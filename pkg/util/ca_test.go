@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func TestValidateCACertificate(t *testing.T) {
+	cases := map[string]struct {
+		cert           string
+		selfSignedOnly bool
+
+		expectErr bool
+	}{
+		"root certificate is accepted": {
+			cert:      dummy.TestCertificate1,
+			expectErr: false,
+		},
+		"root certificate is accepted if only self-signed roots are allowed": {
+			cert:           dummy.TestCertificate3,
+			selfSignedOnly: true,
+			expectErr:      false,
+		},
+		"intermediate certificate is accepted": {
+			cert:      dummy.TestIntermediateCertificate,
+			expectErr: false,
+		},
+		"intermediate certificate is rejected if only self-signed roots are allowed": {
+			cert:           dummy.TestIntermediateCertificate,
+			selfSignedOnly: true,
+			expectErr:      true,
+		},
+		"leaf certificate is rejected": {
+			cert:      dummy.TestLeafCertificate,
+			expectErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			block, _ := pem.Decode([]byte(test.cert))
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("failed to parse dummy certificate: %s", err)
+			}
+
+			err = ValidateCACertificate(cert, test.selfSignedOnly)
+			if test.expectErr != (err != nil) {
+				t.Errorf("expectErr=%v but got err=%v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestEffectiveCARequirement(t *testing.T) {
+	cases := map[string]struct {
+		spec trustapi.BundleSpec
+
+		expRequirement trustapi.CARequirement
+	}{
+		"requirement of a Bundle without a ClusterTrustBundle target is kept": {
+			spec:           trustapi.BundleSpec{CARequirement: trustapi.CARequirementAny},
+			expRequirement: trustapi.CARequirementAny,
+		},
+		"unset requirement of a Bundle with a ClusterTrustBundle target requires CA certificates": {
+			spec:           trustapi.BundleSpec{Target: trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{}}},
+			expRequirement: trustapi.CARequirementCA,
+		},
+		"requirement Any of a Bundle with a ClusterTrustBundle target requires CA certificates": {
+			spec: trustapi.BundleSpec{
+				Target:        trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{}},
+				CARequirement: trustapi.CARequirementAny,
+			},
+			expRequirement: trustapi.CARequirementCA,
+		},
+		"stricter requirement of a Bundle with a ClusterTrustBundle target is kept": {
+			spec: trustapi.BundleSpec{
+				Target:        trustapi.BundleTarget{ClusterTrustBundle: &trustapi.ClusterTrustBundleTarget{}},
+				CARequirement: trustapi.CARequirementSelfSignedCA,
+			},
+			expRequirement: trustapi.CARequirementSelfSignedCA,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			if requirement := EffectiveCARequirement(&test.spec); requirement != test.expRequirement {
+				t.Errorf("expected requirement %q but got %q", test.expRequirement, requirement)
+			}
+		})
+	}
+}
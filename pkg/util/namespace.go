@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// NamespaceSelector converts the given Bundle target namespace selector into
+// a label selector, which is to be matched against NamespaceLabels. Namespace
+// names are selected using the `kubernetes.io/metadata.name` label. A nil
+// namespace selector selects all Namespaces.
+func NamespaceSelector(nsSelector *trustapi.NamespaceSelector) (labels.Selector, error) {
+	if nsSelector == nil {
+		return labels.Everything(), nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels:      nsSelector.MatchLabels,
+		MatchExpressions: nsSelector.MatchExpressions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nsSelector.IncludeNames) > 0 {
+		requirement, err := labels.NewRequirement(corev1.LabelMetadataName, selection.In, nsSelector.IncludeNames)
+		if err != nil {
+			return nil, err
+		}
+
+		selector = selector.Add(*requirement)
+	}
+
+	if len(nsSelector.ExcludeNames) > 0 {
+		requirement, err := labels.NewRequirement(corev1.LabelMetadataName, selection.NotIn, nsSelector.ExcludeNames)
+		if err != nil {
+			return nil, err
+		}
+
+		selector = selector.Add(*requirement)
+	}
+
+	return selector, nil
+}
+
+// NamespaceLabels returns the labels of the given Namespace to match against
+// a selector returned by NamespaceSelector. The `kubernetes.io/metadata.name`
+// label is always set to the name of the Namespace, as the API server does.
+func NamespaceLabels(namespace *corev1.Namespace) labels.Set {
+	set := make(labels.Set, len(namespace.Labels)+1)
+	for key, value := range namespace.Labels {
+		set[key] = value
+	}
+
+	set[corev1.LabelMetadataName] = namespace.Name
+	return set
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func TestNamespaceSelector(t *testing.T) {
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "trusted", "env": "prod"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tenant": "untrusted"}}},
+	}
+
+	cases := map[string]struct {
+		nsSelector *trustapi.NamespaceSelector
+
+		expectMatches []string
+		expectErr     bool
+	}{
+		"nil selector matches all namespaces": {
+			nsSelector:    nil,
+			expectMatches: []string{"default", "kube-system", "team-a", "team-b"},
+		},
+		"empty selector matches all namespaces": {
+			nsSelector:    &trustapi.NamespaceSelector{},
+			expectMatches: []string{"default", "kube-system", "team-a", "team-b"},
+		},
+		"match labels": {
+			nsSelector:    &trustapi.NamespaceSelector{MatchLabels: map[string]string{"env": "prod"}},
+			expectMatches: []string{"team-a"},
+		},
+		"all namespaces except kube-system and untrusted tenants": {
+			nsSelector: &trustapi.NamespaceSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tenant", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"untrusted"}}},
+				ExcludeNames:     []string{"kube-system"},
+			},
+			expectMatches: []string{"default", "team-a"},
+		},
+		"include names": {
+			nsSelector:    &trustapi.NamespaceSelector{IncludeNames: []string{"default", "team-b"}},
+			expectMatches: []string{"default", "team-b"},
+		},
+		"include names and match expressions must both match": {
+			nsSelector: &trustapi.NamespaceSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tenant", Operator: metav1.LabelSelectorOpExists}},
+				IncludeNames:     []string{"default", "team-b"},
+			},
+			expectMatches: []string{"team-b"},
+		},
+		"invalid match expression returns an error": {
+			nsSelector: &trustapi.NamespaceSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tenant", Operator: metav1.LabelSelectorOpIn}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			selector, err := NamespaceSelector(test.nsSelector)
+			if test.expectErr != (err != nil) {
+				t.Fatalf("expectErr=%v but got err=%v", test.expectErr, err)
+			}
+
+			if test.expectErr {
+				return
+			}
+
+			var matches []string
+			for _, namespace := range namespaces {
+				if selector.Matches(NamespaceLabels(namespace)) {
+					matches = append(matches, namespace.Name)
+				}
+			}
+
+			if len(matches) != len(test.expectMatches) {
+				t.Fatalf("expected matches %v but got %v", test.expectMatches, matches)
+			}
+
+			for i := range matches {
+				if matches[i] != test.expectMatches[i] {
+					t.Fatalf("expected matches %v but got %v", test.expectMatches, matches)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// sourceAccess is an access to a resource in the trust Namespace which a
+// Bundle requires the requesting user to be allowed.
+type sourceAccess struct {
+	path *field.Path
+
+	verb     string
+	resource string
+	name     string
+}
+
+// validateSourceAccess runs a SubjectAccessReview for every Secret and
+// ConfigMap in the trust Namespace referenced by the given Bundle, to confirm
+// that the requesting user is allowed to read it. Otherwise, anyone allowed to
+// create Bundles could copy data out of the trust Namespace to which they
+// have no access. Secrets and ConfigMaps read through referenced Bundles are
+// reviewed as well. On update, only references which are not part of the old
+// Bundle are reviewed. Named Secrets and ConfigMaps which the user may read
+// but which don't exist are returned as warnings.
+func (v *validator) validateSourceAccess(ctx context.Context, bundle, oldBundle *trustapi.Bundle) (field.ErrorList, admission.Warnings, error) {
+	accesses, err := v.bundleSourceAccesses(ctx, bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(accesses) == 0 {
+		return nil, nil, nil
+	}
+
+	existing := make(map[sourceAccess]bool)
+	if oldBundle != nil {
+		oldAccesses, err := v.bundleSourceAccesses(ctx, oldBundle)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, access := range oldAccesses {
+			access.path = nil
+			existing[access] = true
+		}
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get admission request to review access to sources: %w", err)
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(req.UserInfo.Extra))
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	var (
+		el       field.ErrorList
+		warnings admission.Warnings
+	)
+	for _, access := range accesses {
+		path := access.path
+
+		access.path = nil
+		if existing[access] {
+			continue
+		}
+		existing[access] = true
+
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   req.UserInfo.Username,
+				UID:    req.UserInfo.UID,
+				Groups: req.UserInfo.Groups,
+				Extra:  extra,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: v.trustNamespace,
+					Verb:      access.verb,
+					Resource:  access.resource,
+					Name:      access.name,
+				},
+			},
+		}
+
+		if err := v.reviewClient.Create(ctx, sar); err != nil {
+			return nil, nil, fmt.Errorf("failed to review access to %s in namespace %q: %w", access.resource, v.trustNamespace, err)
+		}
+
+		if !sar.Status.Allowed {
+			el = append(el, field.Forbidden(path, accessDeniedMessage(req.UserInfo.Username, access, v.trustNamespace)))
+			continue
+		}
+
+		// Only report missing sources to users who may read them, so that
+		// the existence of Secrets isn't disclosed to anyone else.
+		if len(access.name) == 0 {
+			continue
+		}
+
+		exists, err := v.sourceExists(ctx, access)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !exists {
+			warnings = append(warnings, fmt.Sprintf("%s: %s %q does not exist in namespace %q", path, access.resource, access.name, v.trustNamespace))
+		}
+	}
+
+	return el, warnings, nil
+}
+
+// sourceExists returns true if the Secret or ConfigMap of the given access
+// exists in the trust Namespace. Only the metadata of the object is read.
+func (v *validator) sourceExists(ctx context.Context, access sourceAccess) (bool, error) {
+	object := &metav1.PartialObjectMetadata{}
+	object.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(sourceAccessKinds[access.resource]))
+
+	err := v.lister.Get(ctx, client.ObjectKey{Namespace: v.trustNamespace, Name: access.name}, object)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether %s %q exists in namespace %q: %w", access.resource, access.name, v.trustNamespace, err)
+	}
+
+	return true, nil
+}
+
+// sourceAccessKinds maps the resources of source accesses to their kinds.
+var sourceAccessKinds = map[string]string{
+	"configmaps": "ConfigMap",
+	"secrets":    "Secret",
+}
+
+// bundleSourceAccesses returns the accesses to Secrets and ConfigMaps in the
+// trust Namespace which are required to resolve the given Bundle, including
+// the accesses required to resolve any Bundles it references. Accesses of
+// referenced Bundles are reported at the path of the reference. Sources
+// using a selector require listing all resources of their kind.
+func (v *validator) bundleSourceAccesses(ctx context.Context, bundle *trustapi.Bundle) ([]sourceAccess, error) {
+	var accesses []sourceAccess
+	visited := map[string]bool{bundle.Name: true}
+
+	if err := v.appendBundleSourceAccesses(ctx, bundle, nil, visited, &accesses); err != nil {
+		return nil, err
+	}
+
+	return accesses, nil
+}
+
+// appendBundleSourceAccesses appends the accesses required to resolve the
+// given Bundle to accesses. If referencePath is set, all accesses are
+// reported at that path. Bundles which were visited already are skipped, so
+// cyclic references terminate.
+func (v *validator) appendBundleSourceAccesses(ctx context.Context, bundle *trustapi.Bundle, referencePath *field.Path, visited map[string]bool, accesses *[]sourceAccess) error {
+	at := func(path *field.Path) *field.Path {
+		if referencePath != nil {
+			return referencePath
+		}
+
+		return path
+	}
+
+	objectAccess := func(path *field.Path, resource string, selector *trustapi.SourceObjectKeySelector) sourceAccess {
+		if selector.Selector != nil {
+			return sourceAccess{path: at(path.Child("selector")), verb: "list", resource: resource}
+		}
+
+		return sourceAccess{path: at(path.Child("name")), verb: "get", resource: resource, name: selector.Name}
+	}
+
+	for i, source := range bundle.Spec.Sources {
+		path := field.NewPath("spec", "sources").Child("[" + strconv.Itoa(i) + "]")
+
+		if source.ConfigMap != nil {
+			*accesses = append(*accesses, objectAccess(path.Child("configMap"), "configmaps", source.ConfigMap))
+		}
+
+		if source.Secret != nil {
+			*accesses = append(*accesses, objectAccess(path.Child("secret"), "secrets", source.Secret))
+		}
+
+		if source.Bundle != nil && !visited[source.Bundle.Name] {
+			visited[source.Bundle.Name] = true
+
+			var referenced trustapi.Bundle
+			err := v.lister.Get(ctx, client.ObjectKey{Name: source.Bundle.Name}, &referenced)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to get referenced Bundle %q to review access to its sources: %w", source.Bundle.Name, err)
+			}
+
+			if err := v.appendBundleSourceAccesses(ctx, &referenced, at(path.Child("bundle", "name")), visited, accesses); err != nil {
+				return err
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil {
+		path := field.NewPath("spec", "target", "additionalFormats")
+
+		if formats.JKS != nil && formats.JKS.PasswordSecretRef != nil {
+			*accesses = append(*accesses, sourceAccess{path: at(path.Child("jks", "passwordSecretRef", "name")), verb: "get", resource: "secrets", name: formats.JKS.PasswordSecretRef.Name})
+		}
+
+		if formats.PKCS12 != nil && formats.PKCS12.PasswordSecretRef != nil {
+			*accesses = append(*accesses, sourceAccess{path: at(path.Child("pkcs12", "passwordSecretRef", "name")), verb: "get", resource: "secrets", name: formats.PKCS12.PasswordSecretRef.Name})
+		}
+	}
+
+	for i, key := range bundle.Spec.Target.Keys {
+		if key.PasswordSecretRef != nil {
+			path := field.NewPath("spec", "target", "keys").Index(i)
+			*accesses = append(*accesses, sourceAccess{path: at(path.Child("passwordSecretRef", "name")), verb: "get", resource: "secrets", name: key.PasswordSecretRef.Name})
+		}
+	}
+
+	return nil
+}
+
+// accessDeniedMessage returns the validation error message for a denied
+// access.
+func accessDeniedMessage(username string, access sourceAccess, namespace string) string {
+	if len(access.name) == 0 {
+		return fmt.Sprintf("user %q is not allowed to %s %s in namespace %q", username, access.verb, access.resource, namespace)
+	}
+
+	return fmt.Sprintf("user %q is not allowed to %s %s %q in namespace %q", username, access.verb, access.resource, access.name, namespace)
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_validateSourceAccess(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	// allowed are the resource attributes which the test user is allowed.
+	allowed := []authorizationv1.ResourceAttributes{
+		{Namespace: trustNamespace, Verb: "get", Resource: "configmaps", Name: "allowed"},
+		{Namespace: trustNamespace, Verb: "get", Resource: "secrets", Name: "allowed"},
+		{Namespace: trustNamespace, Verb: "list", Resource: "configmaps"},
+	}
+
+	bundleWithSources := func(sources ...trustapi.BundleSource) *trustapi.Bundle {
+		return &trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "testing"},
+			Spec: trustapi.BundleSpec{
+				Sources: append([]trustapi.BundleSource{{InLine: pointer.String(dummy.TestCertificate1)}}, sources...),
+				Target:  trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "ca.crt"}}},
+			},
+		}
+	}
+
+	configMapSource := func(name string) trustapi.BundleSource {
+		return trustapi.BundleSource{ConfigMap: &trustapi.SourceObjectKeySelector{Name: name, KeySelector: trustapi.KeySelector{Key: "ca.crt"}}}
+	}
+
+	secretSource := func(name string) trustapi.BundleSource {
+		return trustapi.BundleSource{Secret: &trustapi.SourceObjectKeySelector{Name: name, KeySelector: trustapi.KeySelector{Key: "ca.crt"}}}
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}}
+
+	tests := map[string]struct {
+		bundle          *trustapi.Bundle
+		oldBundle       *trustapi.Bundle
+		existingObjects []client.Object
+
+		expErr      *string
+		expWarnings admission.Warnings
+		expReviews  int
+	}{
+		"if the Bundle references no Secrets or ConfigMaps, don't review access": {
+			bundle:     bundleWithSources(),
+			expReviews: 0,
+		},
+		"if the user is allowed to read all referenced sources, accept the Bundle": {
+			bundle: bundleWithSources(
+				configMapSource("allowed"),
+				secretSource("allowed"),
+				trustapi.BundleSource{ConfigMap: &trustapi.SourceObjectKeySelector{Selector: selector, KeySelector: trustapi.KeySelector{Key: "ca.crt"}}},
+			),
+			existingObjects: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: "allowed"}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: "allowed"}},
+			},
+			expReviews: 3,
+		},
+		"if the user is allowed to read a referenced source which doesn't exist, accept the Bundle with a warning": {
+			bundle: bundleWithSources(configMapSource("allowed"), secretSource("allowed")),
+			existingObjects: []client.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: "allowed"}},
+			},
+			expWarnings: admission.Warnings{
+				`spec.sources.[2].secret.name: secrets "allowed" does not exist in namespace "trust-namespace"`,
+			},
+			expReviews: 2,
+		},
+		"if the user is not allowed to read a source of a referenced Bundle, reject the Bundle": {
+			bundle: bundleWithSources(
+				trustapi.BundleSource{Bundle: &trustapi.BundleReference{Name: "reads-secret"}},
+			),
+			existingObjects: []client.Object{
+				func() *trustapi.Bundle {
+					bundle := bundleWithSources(secretSource("denied"))
+					bundle.Name = "reads-secret"
+					return bundle
+				}(),
+			},
+			expErr: pointer.String(field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[1]", "bundle", "name"), `user "test-user" is not allowed to get secrets "denied" in namespace "trust-namespace"`),
+			}.ToAggregate().Error()),
+			expReviews: 1,
+		},
+		"if the user is not allowed to read referenced sources, reject the Bundle": {
+			bundle: func() *trustapi.Bundle {
+				bundle := bundleWithSources(
+					configMapSource("denied"),
+					trustapi.BundleSource{Secret: &trustapi.SourceObjectKeySelector{Selector: selector, KeySelector: trustapi.KeySelector{Key: "ca.crt"}}},
+				)
+				bundle.Spec.Target.AdditionalFormats = &trustapi.AdditionalFormats{
+					JKS: &trustapi.JKS{
+						KeySelector:       trustapi.KeySelector{Key: "truststore.jks"},
+						PasswordSecretRef: &trustapi.SecretKeySelector{Name: "denied", KeySelector: trustapi.KeySelector{Key: "password"}},
+					},
+				}
+				return bundle
+			}(),
+			expErr: pointer.String(field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[1]", "configMap", "name"), `user "test-user" is not allowed to get configmaps "denied" in namespace "trust-namespace"`),
+				field.Forbidden(field.NewPath("spec", "sources", "[2]", "secret", "selector"), `user "test-user" is not allowed to list secrets in namespace "trust-namespace"`),
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordSecretRef", "name"), `user "test-user" is not allowed to get secrets "denied" in namespace "trust-namespace"`),
+			}.ToAggregate().Error()),
+			expReviews: 3,
+		},
+		"if a Bundle is updated, only review access to sources which were not referenced before": {
+			bundle:    bundleWithSources(secretSource("denied"), configMapSource("allowed")),
+			oldBundle: bundleWithSources(secretSource("denied")),
+			expWarnings: admission.Warnings{
+				`spec.sources.[2].configMap.name: configmaps "allowed" does not exist in namespace "trust-namespace"`,
+			},
+			expReviews: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var reviews int
+
+			reviewClient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithObjects(test.existingObjects...).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						sar, ok := obj.(*authorizationv1.SubjectAccessReview)
+						if !ok {
+							return c.Create(ctx, obj, opts...)
+						}
+
+						reviews++
+						assert.Equal(t, "test-user", sar.Spec.User)
+						assert.Equal(t, []string{"test-group"}, sar.Spec.Groups)
+
+						for _, attributes := range allowed {
+							if *sar.Spec.ResourceAttributes == attributes {
+								sar.Status.Allowed = true
+							}
+						}
+
+						return nil
+					},
+				}).
+				Build()
+
+			v := &validator{
+				log:              klogr.New(),
+				lister:           reviewClient,
+				authorizeSources: true,
+				trustNamespace:   trustNamespace,
+				reviewClient:     reviewClient,
+			}
+
+			ctx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UserInfo: authenticationv1.UserInfo{Username: "test-user", Groups: []string{"test-group"}},
+				},
+			})
+
+			var oldObj client.Object
+			if test.oldBundle != nil {
+				oldObj = test.oldBundle
+			}
+
+			gotWarnings, gotErr := v.validate(ctx, test.bundle, oldObj)
+			if test.expErr == nil && gotErr != nil {
+				t.Errorf("got an unexpected error: %v", gotErr)
+			} else if test.expErr != nil && (gotErr == nil || *test.expErr != gotErr.Error()) {
+				t.Errorf("wants error: %v got: %v", *test.expErr, gotErr)
+			}
+
+			assert.Equal(t, test.expWarnings, gotWarnings)
+			assert.Equal(t, test.expReviews, reviews)
+		})
+	}
+}
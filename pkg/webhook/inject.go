@@ -0,0 +1,321 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/bundle"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+const (
+	// InjectAnnotationKey is the annotation on Pods holding the name of the
+	// Bundle whose target ConfigMap is to be injected into the Pod.
+	InjectAnnotationKey = "trust.cert-manager.io/inject"
+
+	// InjectEnabledLabelKey is the label which Pods must set to "true" to be
+	// sent to the Pod injection webhook. Labels, unlike annotations, can be
+	// matched by the object selector of the webhook configuration.
+	InjectEnabledLabelKey = "trust.cert-manager.io/inject-enabled"
+
+	// PodInjectionPath is the path which the Pod injection webhook is served
+	// on.
+	PodInjectionPath = "/mutate-v1-pod"
+
+	// injectVolumeName is the name of the volume which is injected into
+	// Pods, holding the target ConfigMap of the Bundle.
+	injectVolumeName = "trust-manager-bundle"
+
+	// injectMountPath is the path which the injected volume is mounted at in
+	// every container of the Pod. The volume is mounted over the system CA
+	// certificates of the image, which are hidden and replaced by the Bundle.
+	injectMountPath = "/etc/ssl/certs"
+
+	// Names of the files which the Bundle formats are mounted as.
+	injectPEMFile    = "ca-certificates.crt"
+	injectJKSFile    = "ca-certificates.jks"
+	injectPKCS12File = "ca-certificates.p12"
+)
+
+// podInjector injects the target ConfigMap of a Bundle into Pods which
+// request it using the InjectAnnotationKey annotation and the
+// InjectEnabledLabelKey label.
+type podInjector struct {
+	log logr.Logger
+
+	// lister is used to read the Bundles which are injected.
+	lister client.Reader
+
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &podInjector{}
+
+// Handle mounts the target ConfigMap of the requested Bundle into every
+// container of the Pod, and sets the environment variables which point
+// common languages and tools to the formats which the Bundle produces.
+// Environment variables which are already set on a container are left
+// untouched.
+func (p *podInjector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var pod corev1.Pod
+	if err := p.decoder.Decode(req, &pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	bundleName, ok := pod.Annotations[InjectAnnotationKey]
+	if !ok || pod.Labels[InjectEnabledLabelKey] != "true" {
+		return admission.Allowed("no trust injection requested")
+	}
+
+	log := p.log.WithValues("bundle", bundleName, "namespace", req.Namespace)
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == injectVolumeName {
+			return admission.Allowed("trust bundle already injected")
+		}
+	}
+
+	var trustBundle trustapi.Bundle
+	err := p.lister.Get(ctx, client.ObjectKey{Name: bundleName}, &trustBundle)
+	if apierrors.IsNotFound(err) {
+		return admission.Denied(fmt.Sprintf("Bundle %q requested by the %s annotation does not exist", bundleName, InjectAnnotationKey))
+	}
+
+	if err != nil {
+		log.Error(err, "failed to get bundle")
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to get Bundle %q: %w", bundleName, err))
+	}
+
+	target := trustBundle.Spec.Target
+	if target.ConfigMap == nil {
+		return admission.Denied(fmt.Sprintf("Bundle %q has no ConfigMap target which can be injected", bundleName))
+	}
+
+	// The target ConfigMap only exists in Namespaces selected by the Bundle.
+	// Pods mounting it in any other Namespace would never start.
+	selected, err := p.namespaceSelected(ctx, target.NamespaceSelector, req.Namespace)
+	if err != nil {
+		log.Error(err, "failed to match namespace against bundle namespace selector")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if !selected {
+		return admission.Denied(fmt.Sprintf("Bundle %q is not synced to namespace %q, as the namespace doesn't match the Bundle namespace selector", bundleName, req.Namespace))
+	}
+
+	files := injectFiles(&target)
+	env := injectEnv(&target)
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: injectVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: injectConfigMapName(&trustBundle)},
+				Items:                files,
+			},
+		},
+	})
+
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			if err := injectContainer(&containers[i], env); err != nil {
+				return admission.Denied(err.Error())
+			}
+		}
+	}
+
+	marshaledPod, err := json.Marshal(&pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	log.V(2).Info("injected trust bundle into pod")
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+}
+
+// namespaceSelected returns true if the given Namespace is selected by the
+// given Bundle target namespace selector.
+func (p *podInjector) namespaceSelected(ctx context.Context, nsSelector *trustapi.NamespaceSelector, namespaceName string) (bool, error) {
+	if nsSelector == nil {
+		return true, nil
+	}
+
+	selector, err := util.NamespaceSelector(nsSelector)
+	if err != nil {
+		return false, fmt.Errorf("failed to build namespace selector: %w", err)
+	}
+
+	var namespace corev1.Namespace
+	if err := p.lister.Get(ctx, client.ObjectKey{Name: namespaceName}, &namespace); err != nil {
+		return false, fmt.Errorf("failed to get namespace %q: %w", namespaceName, err)
+	}
+
+	return selector.Matches(util.NamespaceLabels(&namespace)), nil
+}
+
+// injectContainer mounts the injected volume into the given container, and
+// sets all given environment variables which the container doesn't set
+// already. Returns an error if the container already mounts another volume at
+// the injected mount path.
+func injectContainer(container *corev1.Container, env []corev1.EnvVar) error {
+	for _, mount := range container.VolumeMounts {
+		if mount.MountPath == injectMountPath {
+			return fmt.Errorf("container %q already mounts volume %q at %s", container.Name, mount.Name, injectMountPath)
+		}
+	}
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      injectVolumeName,
+		MountPath: injectMountPath,
+		ReadOnly:  true,
+	})
+
+	existing := make(map[string]bool, len(container.Env))
+	for _, envVar := range container.Env {
+		existing[envVar.Name] = true
+	}
+
+	for _, envVar := range env {
+		if !existing[envVar.Name] {
+			container.Env = append(container.Env, envVar)
+		}
+	}
+
+	return nil
+}
+
+// injectConfigMapName returns the name of the target ConfigMap of the given
+// Bundle, which defaults to the name of the Bundle.
+func injectConfigMapName(trustBundle *trustapi.Bundle) string {
+	if len(trustBundle.Spec.Target.ConfigMap.Name) > 0 {
+		return trustBundle.Spec.Target.ConfigMap.Name
+	}
+
+	return trustBundle.Name
+}
+
+// injectTrustStore is a binary trust store which the given Bundle target
+// produces, and which can be used by Java.
+type injectTrustStore struct {
+	key       string
+	file      string
+	storeType string
+
+	// password is the password of the trust store, if known. Passwords which
+	// are read from a Secret in the trust Namespace are not injected.
+	password *string
+}
+
+// injectJavaTrustStore returns the binary trust store of the given Bundle
+// target which is used for Java. JKS is preferred over PKCS12, and additional
+// formats over target keys. Returns nil if the target has no binary trust
+// store.
+func injectJavaTrustStore(target *trustapi.BundleTarget) *injectTrustStore {
+	defaultJKSPassword := bundle.DefaultJKSPassword
+
+	if formats := target.AdditionalFormats; formats != nil && formats.JKS != nil {
+		return &injectTrustStore{key: formats.JKS.Key, file: injectJKSFile, storeType: "JKS", password: injectPassword(formats.JKS.Password, formats.JKS.PasswordSecretRef, &defaultJKSPassword)}
+	}
+
+	for _, key := range target.Keys {
+		if key.Format == trustapi.TargetFormatJKS {
+			return &injectTrustStore{key: key.Key, file: injectJKSFile, storeType: "JKS", password: injectPassword(key.Password, key.PasswordSecretRef, &defaultJKSPassword)}
+		}
+	}
+
+	if formats := target.AdditionalFormats; formats != nil && formats.PKCS12 != nil {
+		return &injectTrustStore{key: formats.PKCS12.Key, file: injectPKCS12File, storeType: "PKCS12", password: injectPassword(formats.PKCS12.Password, formats.PKCS12.PasswordSecretRef, nil)}
+	}
+
+	for _, key := range target.Keys {
+		if key.Format == trustapi.TargetFormatPKCS12 {
+			return &injectTrustStore{key: key.Key, file: injectPKCS12File, storeType: "PKCS12", password: injectPassword(key.Password, key.PasswordSecretRef, nil)}
+		}
+	}
+
+	return nil
+}
+
+// injectPassword returns the password of a trust store which can be injected,
+// or nil if the password is unknown or empty. Passwords containing whitespace
+// can't be passed in JAVA_TOOL_OPTIONS, so aren't injected either.
+func injectPassword(password *string, secretRef *trustapi.SecretKeySelector, defaultPassword *string) *string {
+	switch {
+	case password != nil && len(*password) > 0 && !strings.ContainsAny(*password, " \t\r\n"):
+		return password
+	case password != nil || secretRef != nil:
+		return nil
+	default:
+		return defaultPassword
+	}
+}
+
+// injectFiles returns the keys of the target ConfigMap which are mounted into
+// Pods, and the files they are mounted as.
+func injectFiles(target *trustapi.BundleTarget) []corev1.KeyToPath {
+	files := []corev1.KeyToPath{{Key: target.ConfigMap.Key, Path: injectPEMFile}}
+
+	if trustStore := injectJavaTrustStore(target); trustStore != nil {
+		files = append(files, corev1.KeyToPath{Key: trustStore.key, Path: trustStore.file})
+	}
+
+	return files
+}
+
+// injectEnv returns the environment variables which point common languages
+// and tools to the formats which the given Bundle target produces.
+func injectEnv(target *trustapi.BundleTarget) []corev1.EnvVar {
+	pemFile := path.Join(injectMountPath, injectPEMFile)
+
+	env := []corev1.EnvVar{
+		// OpenSSL, Go, Ruby and most tools linked against OpenSSL.
+		{Name: "SSL_CERT_FILE", Value: pemFile},
+		// Python requests.
+		{Name: "REQUESTS_CA_BUNDLE", Value: pemFile},
+		// Node.js.
+		{Name: "NODE_EXTRA_CA_CERTS", Value: pemFile},
+	}
+
+	if trustStore := injectJavaTrustStore(target); trustStore != nil {
+		options := []string{
+			"-Djavax.net.ssl.trustStore=" + path.Join(injectMountPath, trustStore.file),
+			"-Djavax.net.ssl.trustStoreType=" + trustStore.storeType,
+		}
+
+		if trustStore.password != nil {
+			options = append(options, "-Djavax.net.ssl.trustStorePassword="+*trustStore.password)
+		}
+
+		env = append(env, corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: strings.Join(options, " ")})
+	}
+
+	return env
+}
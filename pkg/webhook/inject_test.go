@@ -0,0 +1,307 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_podInjector(t *testing.T) {
+	pemOnlyBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "pem-only"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "ca.crt"}}},
+		},
+	}
+
+	jksBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "jks"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{Name: "ca-bundle", KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "truststore.jks"}},
+				},
+			},
+		},
+	}
+
+	pkcs12Bundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "pkcs12"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+				Keys: []trustapi.TargetKey{
+					{KeySelector: trustapi.KeySelector{Key: "truststore.p12"}, Format: trustapi.TargetFormatPKCS12, Password: pointer.String("secret")},
+				},
+			},
+		},
+	}
+
+	selectedBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "selected"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+				NamespaceSelector: &trustapi.NamespaceSelector{MatchLabels: map[string]string{"trust": "enabled"}},
+			},
+		},
+	}
+
+	includedBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "included"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "ca.crt"}},
+				NamespaceSelector: &trustapi.NamespaceSelector{IncludeNames: []string{"test"}},
+			},
+		},
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+	secretBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{Secret: &trustapi.KeySelector{Key: "ca.crt"}},
+		},
+	}
+
+	pemEnv := []corev1.EnvVar{
+		{Name: "SSL_CERT_FILE", Value: "/etc/ssl/certs/ca-certificates.crt"},
+		{Name: "REQUESTS_CA_BUNDLE", Value: "/etc/ssl/certs/ca-certificates.crt"},
+		{Name: "NODE_EXTRA_CA_CERTS", Value: "/etc/ssl/certs/ca-certificates.crt"},
+	}
+
+	injectedMount := corev1.VolumeMount{Name: "trust-manager-bundle", MountPath: "/etc/ssl/certs", ReadOnly: true}
+
+	podWithAnnotation := func(bundleName string, containers ...corev1.Container) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test",
+				Namespace:   "test",
+				Labels:      map[string]string{InjectEnabledLabelKey: "true"},
+				Annotations: map[string]string{InjectAnnotationKey: bundleName},
+			},
+			Spec: corev1.PodSpec{Containers: containers},
+		}
+	}
+
+	tests := map[string]struct {
+		pod *corev1.Pod
+
+		expAllowed bool
+		expMessage string
+		expPod     *corev1.Pod
+	}{
+		"if the Pod is not annotated, don't inject": {
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			},
+			expAllowed: true,
+			expMessage: "no trust injection requested",
+		},
+		"if the Pod is annotated but not labelled to enable injection, don't inject": {
+			pod: func() *corev1.Pod {
+				pod := podWithAnnotation("pem-only", corev1.Container{Name: "app"})
+				pod.Labels = nil
+				return pod
+			}(),
+			expAllowed: true,
+			expMessage: "no trust injection requested",
+		},
+		"if the annotated Bundle does not exist, deny the Pod": {
+			pod:        podWithAnnotation("not-found", corev1.Container{Name: "app"}),
+			expAllowed: false,
+			expMessage: `Bundle "not-found" requested by the trust.cert-manager.io/inject annotation does not exist`,
+		},
+		"if the annotated Bundle has no ConfigMap target, deny the Pod": {
+			pod:        podWithAnnotation("secret", corev1.Container{Name: "app"}),
+			expAllowed: false,
+			expMessage: `Bundle "secret" has no ConfigMap target which can be injected`,
+		},
+		"if the Namespace of the Pod doesn't match the Bundle namespace selector, deny the Pod": {
+			pod:        podWithAnnotation("selected", corev1.Container{Name: "app"}),
+			expAllowed: false,
+			expMessage: `Bundle "selected" is not synced to namespace "test", as the namespace doesn't match the Bundle namespace selector`,
+		},
+		"if the Namespace of the Pod matches the Bundle namespace selector, inject the ConfigMap": {
+			pod:        podWithAnnotation("included", corev1.Container{Name: "app"}),
+			expAllowed: true,
+			expPod: func() *corev1.Pod {
+				pod := podWithAnnotation("included", corev1.Container{Name: "app", VolumeMounts: []corev1.VolumeMount{injectedMount}, Env: pemEnv})
+				pod.Spec.Volumes = []corev1.Volume{{
+					Name: "trust-manager-bundle",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "included"},
+						Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca-certificates.crt"}},
+					}},
+				}}
+				return pod
+			}(),
+		},
+		"if a container already mounts a volume at /etc/ssl/certs, deny the Pod": {
+			pod: podWithAnnotation("pem-only", corev1.Container{
+				Name:         "app",
+				VolumeMounts: []corev1.VolumeMount{{Name: "certs", MountPath: "/etc/ssl/certs"}},
+			}),
+			expAllowed: false,
+			expMessage: `container "app" already mounts volume "certs" at /etc/ssl/certs`,
+		},
+		"if the Bundle produces PEM only, inject the ConfigMap and the PEM environment variables into all containers": {
+			pod: func() *corev1.Pod {
+				pod := podWithAnnotation("pem-only", corev1.Container{Name: "app"})
+				pod.Spec.InitContainers = []corev1.Container{{Name: "init"}}
+				return pod
+			}(),
+			expAllowed: true,
+			expPod: func() *corev1.Pod {
+				pod := podWithAnnotation("pem-only", corev1.Container{Name: "app", VolumeMounts: []corev1.VolumeMount{injectedMount}, Env: pemEnv})
+				pod.Spec.InitContainers = []corev1.Container{{Name: "init", VolumeMounts: []corev1.VolumeMount{injectedMount}, Env: pemEnv}}
+				pod.Spec.Volumes = []corev1.Volume{{
+					Name: "trust-manager-bundle",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "pem-only"},
+						Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca-certificates.crt"}},
+					}},
+				}}
+				return pod
+			}(),
+		},
+		"if the Bundle produces JKS, inject the trust store and JAVA_TOOL_OPTIONS, but keep environment variables which are already set": {
+			pod: podWithAnnotation("jks", corev1.Container{
+				Name: "app",
+				Env:  []corev1.EnvVar{{Name: "SSL_CERT_FILE", Value: "/custom.crt"}},
+			}),
+			expAllowed: true,
+			expPod: func() *corev1.Pod {
+				pod := podWithAnnotation("jks", corev1.Container{
+					Name:         "app",
+					VolumeMounts: []corev1.VolumeMount{injectedMount},
+					Env: []corev1.EnvVar{
+						{Name: "SSL_CERT_FILE", Value: "/custom.crt"},
+						pemEnv[1], pemEnv[2],
+						{Name: "JAVA_TOOL_OPTIONS", Value: "-Djavax.net.ssl.trustStore=/etc/ssl/certs/ca-certificates.jks -Djavax.net.ssl.trustStoreType=JKS -Djavax.net.ssl.trustStorePassword=changeit"},
+					},
+				})
+				pod.Spec.Volumes = []corev1.Volume{{
+					Name: "trust-manager-bundle",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "ca-bundle"},
+						Items: []corev1.KeyToPath{
+							{Key: "ca.crt", Path: "ca-certificates.crt"},
+							{Key: "truststore.jks", Path: "ca-certificates.jks"},
+						},
+					}},
+				}}
+				return pod
+			}(),
+		},
+		"if the Bundle produces a PKCS12 target key, inject the trust store and JAVA_TOOL_OPTIONS": {
+			pod:        podWithAnnotation("pkcs12", corev1.Container{Name: "app"}),
+			expAllowed: true,
+			expPod: func() *corev1.Pod {
+				pod := podWithAnnotation("pkcs12", corev1.Container{
+					Name:         "app",
+					VolumeMounts: []corev1.VolumeMount{injectedMount},
+					Env: append(append([]corev1.EnvVar{}, pemEnv...),
+						corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-Djavax.net.ssl.trustStore=/etc/ssl/certs/ca-certificates.p12 -Djavax.net.ssl.trustStoreType=PKCS12 -Djavax.net.ssl.trustStorePassword=secret"},
+					),
+				})
+				pod.Spec.Volumes = []corev1.Volume{{
+					Name: "trust-manager-bundle",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "pkcs12"},
+						Items: []corev1.KeyToPath{
+							{Key: "ca.crt", Path: "ca-certificates.crt"},
+							{Key: "truststore.p12", Path: "ca-certificates.p12"},
+						},
+					}},
+				}}
+				return pod
+			}(),
+		},
+		"if the Pod has already been injected, don't inject again": {
+			pod: func() *corev1.Pod {
+				pod := podWithAnnotation("pem-only", corev1.Container{Name: "app"})
+				pod.Spec.Volumes = []corev1.Volume{{Name: "trust-manager-bundle"}}
+				return pod
+			}(),
+			expAllowed: true,
+			expMessage: "trust bundle already injected",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lister := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithObjects([]client.Object{pemOnlyBundle, jksBundle, pkcs12Bundle, selectedBundle, includedBundle, secretBundle, namespace}...).
+				Build()
+
+			injector := &podInjector{
+				log:     klogr.New(),
+				lister:  lister,
+				decoder: admission.NewDecoder(trustapi.GlobalScheme),
+			}
+
+			raw, err := json.Marshal(test.pod)
+			require.NoError(t, err)
+
+			resp := injector.Handle(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Namespace: test.pod.Namespace,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+
+			assert.Equal(t, test.expAllowed, resp.Allowed)
+			if len(test.expMessage) > 0 {
+				assert.Equal(t, test.expMessage, resp.Result.Message)
+			}
+
+			if test.expPod == nil {
+				assert.Empty(t, resp.Patches)
+				return
+			}
+
+			// The patch operations must equal the operations of the patch to
+			// the expected Pod, in any order.
+			expRaw, err := json.Marshal(test.expPod)
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, admission.PatchResponseFromRaw(raw, expRaw).Patches, resp.Patches)
+		})
+	}
+}
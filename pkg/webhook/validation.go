@@ -18,34 +18,77 @@ package webhook
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/cert-manager/trust-manager/pkg/apis/trust"
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
 )
 
+// minURLRefreshInterval is the shortest allowed refresh interval for URL
+// sources.
+const minURLRefreshInterval = time.Minute
+
 // validator validates against trust.cert-manager.io resources.
 type validator struct {
 	log logr.Logger
 
+	// lister is used to read other Bundles, which are referenced as sources
+	// or may target the same ConfigMaps.
+	lister client.Reader
+
+	// expiryWarningWindow is the window before expiry in which certificates of
+	// inline sources are returned as warnings. Disabled if zero.
+	expiryWarningWindow time.Duration
+
+	// clock returns time which can be overwritten for testing.
+	clock clock.Clock
+
+	// authorizeSources, if true, requires the user creating or updating a
+	// Bundle to be allowed to read the Secrets and ConfigMaps it references
+	// in the trust Namespace, directly or through other Bundles.
+	authorizeSources bool
+
+	// trustNamespace is the Namespace which Bundle sources are read from.
+	trustNamespace string
+
+	// reviewClient is used to create SubjectAccessReviews.
+	reviewClient client.Client
+
 	lock sync.RWMutex
 }
 
 var _ admission.CustomValidator = &validator{}
 
 func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
-	return v.validate(ctx, obj)
+	return v.validate(ctx, obj, nil)
 }
 
 func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
-	return v.validate(ctx, newObj)
+	return v.validate(ctx, newObj, oldObj)
 }
 
 func (v *validator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
@@ -53,7 +96,7 @@ func (v *validator) ValidateDelete(ctx context.Context, obj runtime.Object) (adm
 	return nil, nil
 }
 
-func (v *validator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *validator) validate(ctx context.Context, obj, oldObj runtime.Object) (admission.Warnings, error) {
 	bundle, ok := obj.(*trustapi.Bundle)
 	if !ok {
 		return nil, fmt.Errorf("expected a Bundle, but got a %T", obj)
@@ -79,8 +122,13 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) (admission
 			sourceCount++
 			unionCount++
 
-			if len(configMap.Name) == 0 {
-				el = append(el, field.Invalid(path.Child("name"), configMap.Name, "source configMap name must be defined"))
+			switch {
+			case len(configMap.Name) == 0 && configMap.Selector == nil:
+				el = append(el, field.Invalid(path.Child("name"), configMap.Name, "source configMap name or selector must be defined"))
+			case len(configMap.Name) > 0 && configMap.Selector != nil:
+				el = append(el, field.Forbidden(path, "source configMap must define exactly one of name or selector"))
+			case configMap.Selector != nil:
+				el = append(el, validateSourceSelector(path.Child("selector"), configMap.Selector)...)
 			}
 			if len(configMap.Key) == 0 {
 				el = append(el, field.Invalid(path.Child("key"), configMap.Key, "source configMap key must be defined"))
@@ -92,8 +140,13 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) (admission
 			sourceCount++
 			unionCount++
 
-			if len(secret.Name) == 0 {
-				el = append(el, field.Invalid(path.Child("name"), secret.Name, "source secret name must be defined"))
+			switch {
+			case len(secret.Name) == 0 && secret.Selector == nil:
+				el = append(el, field.Invalid(path.Child("name"), secret.Name, "source secret name or selector must be defined"))
+			case len(secret.Name) > 0 && secret.Selector != nil:
+				el = append(el, field.Forbidden(path, "source secret must define exactly one of name or selector"))
+			case secret.Selector != nil:
+				el = append(el, validateSourceSelector(path.Child("selector"), secret.Selector)...)
 			}
 			if len(secret.Key) == 0 {
 				el = append(el, field.Invalid(path.Child("key"), secret.Key, "source secret key must be defined"))
@@ -101,8 +154,57 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) (admission
 		}
 
 		if source.InLine != nil {
+			path := path.Child("inLine")
+			sourceCount++
+			unionCount++
+
+			certificates, err := parseInLineCertificates(*source.InLine)
+			if err != nil {
+				el = append(el, field.Invalid(path, field.OmitValueType{}, err.Error()))
+			} else {
+				el = append(el, validateCARequirement(path, certificates, util.EffectiveCARequirement(&bundle.Spec))...)
+				warnings = append(warnings, v.expiryWarnings(path, certificates)...)
+			}
+		}
+
+		if bundleRef := source.Bundle; bundleRef != nil {
+			path := path.Child("bundle")
+			sourceCount++
+			unionCount++
+
+			if len(bundleRef.Name) == 0 {
+				el = append(el, field.Invalid(path.Child("name"), bundleRef.Name, "source bundle name must be defined"))
+			}
+		}
+
+		if urlSource := source.URL; urlSource != nil {
 			sourceCount++
 			unionCount++
+
+			el = append(el, validateURLSource(path.Child("url"), urlSource)...)
+		}
+
+		if clusterTrustBundle := source.ClusterTrustBundle; clusterTrustBundle != nil {
+			path := path.Child("clusterTrustBundle")
+			sourceCount++
+			unionCount++
+
+			switch {
+			case len(clusterTrustBundle.Name) == 0 && len(clusterTrustBundle.SignerName) == 0:
+				el = append(el, field.Invalid(path.Child("name"), clusterTrustBundle.Name, "source clusterTrustBundle name or signerName must be defined"))
+			case len(clusterTrustBundle.Name) > 0 && len(clusterTrustBundle.SignerName) > 0:
+				el = append(el, field.Forbidden(path, "source clusterTrustBundle must define exactly one of name or signerName"))
+			case len(clusterTrustBundle.SignerName) > 0:
+				el = append(el, validateSignerName(path.Child("signerName"), clusterTrustBundle.SignerName)...)
+			}
+
+			if clusterTrustBundle.Selector != nil {
+				if len(clusterTrustBundle.SignerName) == 0 {
+					el = append(el, field.Forbidden(path.Child("selector"), "source clusterTrustBundle selector may only be set together with signerName"))
+				} else {
+					el = append(el, validateSourceSelector(path.Child("selector"), clusterTrustBundle.Selector)...)
+				}
+			}
 		}
 
 		if source.UseDefaultCAs != nil {
@@ -132,29 +234,158 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) (admission
 		))
 	}
 
+	cycleErrs, err := v.validateBundleSourceCycles(ctx, bundle, path.Child("sources"))
+	if err != nil {
+		return nil, err
+	}
+	el = append(el, cycleErrs...)
+
 	if target := bundle.Spec.Target.ConfigMap; target != nil {
+		targetName := configMapTargetName(bundle)
+
 		path := path.Child("sources")
 		for i, source := range bundle.Spec.Sources {
-			if source.ConfigMap != nil && source.ConfigMap.Name == bundle.Name && source.ConfigMap.Key == target.Key {
+			if source.ConfigMap != nil && source.ConfigMap.Name == targetName && source.ConfigMap.Key == target.Key {
 				el = append(el, field.Forbidden(path.Child(fmt.Sprintf("[%d]", i), "configMap", source.ConfigMap.Name, source.ConfigMap.Key), "cannot define the same source as target"))
 			}
 		}
 	}
 
-	if configMap := bundle.Spec.Target.ConfigMap; configMap == nil {
-		el = append(el, field.Invalid(path.Child("target", "configMap"), configMap, "target configMap must be defined"))
-	} else if len(configMap.Key) == 0 {
+	if target := bundle.Spec.Target.Secret; target != nil {
+		path := path.Child("sources")
+		for i, source := range bundle.Spec.Sources {
+			if source.Secret != nil && source.Secret.Name == bundle.Name && source.Secret.Key == target.Key {
+				el = append(el, field.Forbidden(path.Child(fmt.Sprintf("[%d]", i), "secret", source.Secret.Name, source.Secret.Key), "cannot define the same source as target"))
+			}
+		}
+	}
+
+	if target := bundle.Spec.Target.ClusterTrustBundle; target != nil {
+		targetName := bundle.Name
+		if len(target.SignerName) > 0 {
+			targetName = strings.ReplaceAll(target.SignerName, "/", ":") + ":" + bundle.Name
+		}
+
+		path := path.Child("sources")
+		for i, source := range bundle.Spec.Sources {
+			if source.ClusterTrustBundle == nil {
+				continue
+			}
+
+			// A source selecting the ClusterTrustBundles of the target signer
+			// would select the target itself.
+			if source.ClusterTrustBundle.Name == targetName || (len(target.SignerName) > 0 && source.ClusterTrustBundle.SignerName == target.SignerName) {
+				el = append(el, field.Forbidden(path.Child(fmt.Sprintf("[%d]", i), "clusterTrustBundle"), "cannot define the same source as target"))
+			}
+		}
+	}
+
+	configMap := bundle.Spec.Target.ConfigMap
+	secret := bundle.Spec.Target.Secret
+
+	clusterTrustBundle := bundle.Spec.Target.ClusterTrustBundle
+
+	if configMap == nil && secret == nil && clusterTrustBundle == nil {
+		el = append(el, field.Forbidden(path.Child("target"), "must define at least one target configMap, secret or clusterTrustBundle"))
+	}
+
+	if configMap != nil && len(configMap.Key) == 0 {
 		el = append(el, field.Invalid(path.Child("target", "configMap", "key"), configMap.Key, "target configMap key must be defined"))
-	} else if bundle.Spec.Target.AdditionalFormats != nil && bundle.Spec.Target.AdditionalFormats.JKS != nil {
-		if bundle.Spec.Target.AdditionalFormats.JKS.Key == configMap.Key {
-			el = append(el, field.Invalid(path.Child("target", "additionalFormats", "jks", "key"), bundle.Spec.Target.AdditionalFormats.JKS.Key, "target JKS key must be different to configMap key"))
+	}
+
+	if configMap != nil && len(configMap.Name) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(configMap.Name) {
+			el = append(el, field.Invalid(path.Child("target", "configMap", "name"), configMap.Name, msg))
 		}
 	}
 
-	if nsSel := bundle.Spec.Target.NamespaceSelector; nsSel != nil && len(nsSel.MatchLabels) > 0 {
-		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: nsSel.MatchLabels}); err != nil {
-			el = append(el, field.Invalid(path.Child("target", "namespaceSelector", "matchLabels"), nsSel.MatchLabels, err.Error()))
+	if configMap != nil {
+		nameErrs, err := v.validateConfigMapTargetName(ctx, bundle, path.Child("target", "configMap", "name"))
+		if err != nil {
+			return nil, err
+		}
+		el = append(el, nameErrs...)
+	}
+
+	if secret != nil && len(secret.Key) == 0 {
+		el = append(el, field.Invalid(path.Child("target", "secret", "key"), secret.Key, "target secret key must be defined"))
+	}
+
+	if clusterTrustBundle != nil && len(clusterTrustBundle.SignerName) > 0 {
+		el = append(el, validateSignerName(path.Child("target", "clusterTrustBundle", "signerName"), clusterTrustBundle.SignerName)...)
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.JKS != nil {
+		path := path.Child("target", "additionalFormats", "jks")
+
+		if len(formats.JKS.Key) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), formats.JKS.Key, "target JKS key must be defined"))
+		}
+
+		if configMap != nil && formats.JKS.Key == configMap.Key {
+			el = append(el, field.Invalid(path.Child("key"), formats.JKS.Key, "target JKS key must be different to configMap key"))
+		}
+
+		if secret != nil && formats.JKS.Key == secret.Key {
+			el = append(el, field.Invalid(path.Child("key"), formats.JKS.Key, "target JKS key must be different to secret key"))
+		}
+
+		el = append(el, validatePassword(path, formats.JKS.Password, formats.JKS.PasswordSecretRef)...)
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.PKCS12 != nil {
+		path := path.Child("target", "additionalFormats", "pkcs12")
+
+		if len(formats.PKCS12.Key) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), formats.PKCS12.Key, "target PKCS12 key must be defined"))
+		}
+
+		if configMap != nil && formats.PKCS12.Key == configMap.Key {
+			el = append(el, field.Invalid(path.Child("key"), formats.PKCS12.Key, "target PKCS12 key must be different to configMap key"))
+		}
+
+		if secret != nil && formats.PKCS12.Key == secret.Key {
+			el = append(el, field.Invalid(path.Child("key"), formats.PKCS12.Key, "target PKCS12 key must be different to secret key"))
+		}
+
+		if formats.JKS != nil && formats.PKCS12.Key == formats.JKS.Key {
+			el = append(el, field.Invalid(path.Child("key"), formats.PKCS12.Key, "target PKCS12 key must be different to JKS key"))
+		}
+
+		el = append(el, validatePassword(path, formats.PKCS12.Password, formats.PKCS12.PasswordSecretRef)...)
+	}
+
+	if keys := bundle.Spec.Target.Keys; len(keys) > 0 {
+		el = append(el, validateTargetKeys(path.Child("target", "keys"), &bundle.Spec.Target)...)
+	}
+
+	if filter := bundle.Spec.Filter; filter != nil {
+		el = append(el, validateCertificateMatchers(path.Child("filter", "include"), filter.Include)...)
+		el = append(el, validateCertificateMatchers(path.Child("filter", "exclude"), filter.Exclude)...)
+	}
+
+	if policy := bundle.Spec.CryptoPolicy; policy != nil {
+		el = append(el, validateCryptoPolicy(path.Child("cryptoPolicy"), policy)...)
+	}
+
+	if nsSel := bundle.Spec.Target.NamespaceSelector; nsSel != nil {
+		el = append(el, validateNamespaceSelector(path.Child("target", "namespaceSelector"), nsSel)...)
+	}
+
+	if metadata := bundle.Spec.Target.Metadata; metadata != nil {
+		el = append(el, validateTargetMetadata(path.Child("target", "metadata"), metadata)...)
+	}
+
+	if v.authorizeSources {
+		oldBundle, _ := oldObj.(*trustapi.Bundle)
+
+		accessErrs, accessWarnings, err := v.validateSourceAccess(ctx, bundle, oldBundle)
+		if err != nil {
+			return warnings, err
 		}
+
+		el = append(el, accessErrs...)
+		warnings = append(warnings, accessWarnings...)
 	}
 
 	path = field.NewPath("status")
@@ -170,3 +401,443 @@ func (v *validator) validate(ctx context.Context, obj runtime.Object) (admission
 	return warnings, el.ToAggregate()
 
 }
+
+// validateNamespaceSelector validates the namespace selector of a Bundle
+// target.
+func validateNamespaceSelector(path *field.Path, nsSel *trustapi.NamespaceSelector) field.ErrorList {
+	var el field.ErrorList
+
+	if len(nsSel.MatchLabels) > 0 {
+		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: nsSel.MatchLabels}); err != nil {
+			el = append(el, field.Invalid(path.Child("matchLabels"), nsSel.MatchLabels, err.Error()))
+		}
+	}
+
+	for i, expression := range nsSel.MatchExpressions {
+		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{expression}}); err != nil {
+			el = append(el, field.Invalid(path.Child("matchExpressions").Index(i), expression, err.Error()))
+		}
+	}
+
+	excluded := make(map[string]bool, len(nsSel.ExcludeNames))
+	for i, name := range nsSel.ExcludeNames {
+		for _, msg := range validation.IsDNS1123Label(name) {
+			el = append(el, field.Invalid(path.Child("excludeNames").Index(i), name, msg))
+		}
+
+		excluded[name] = true
+	}
+
+	for i, name := range nsSel.IncludeNames {
+		for _, msg := range validation.IsDNS1123Label(name) {
+			el = append(el, field.Invalid(path.Child("includeNames").Index(i), name, msg))
+		}
+
+		if excluded[name] {
+			el = append(el, field.Invalid(path.Child("includeNames").Index(i), name, "namespace must not be both included and excluded"))
+		}
+	}
+
+	return el
+}
+
+// validateSignerName validates that the given signer name has the form
+// <domain>/<path>, as required for signers of ClusterTrustBundles.
+func validateSignerName(path *field.Path, signerName string) field.ErrorList {
+	domain, signerPath, ok := strings.Cut(signerName, "/")
+	if !ok || len(signerPath) == 0 {
+		return field.ErrorList{field.Invalid(path, signerName, "signer name must be of the form <domain>/<path>")}
+	}
+
+	var el field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(domain) {
+		el = append(el, field.Invalid(path, signerName, "signer name domain is invalid: "+msg))
+	}
+
+	return el
+}
+
+// validateTargetMetadata validates the labels and annotations which are set
+// on the Bundle targets. Annotations in the trust.cert-manager.io domain are
+// reserved for trust-manager, for example to record the hash of the target data.
+func validateTargetMetadata(path *field.Path, metadata *trustapi.TargetMetadata) field.ErrorList {
+	el := metav1validation.ValidateLabels(metadata.Labels, path.Child("labels"))
+	el = append(el, apivalidation.ValidateAnnotations(metadata.Annotations, path.Child("annotations"))...)
+
+	for key := range metadata.Annotations {
+		if strings.HasPrefix(key, trust.GroupName+"/") {
+			el = append(el, field.Forbidden(path.Child("annotations").Key(key), fmt.Sprintf("annotations in the %s domain are reserved", trust.GroupName)))
+		}
+	}
+
+	return el
+}
+
+// validateCertificateMatchers validates the include or exclude rules of a
+// Bundle filter.
+func validateCertificateMatchers(path *field.Path, matchers []trustapi.CertificateMatcher) field.ErrorList {
+	var el field.ErrorList
+
+	for i, matcher := range matchers {
+		path := path.Index(i)
+
+		var count int
+		if len(matcher.SHA256) > 0 {
+			count++
+
+			if digest, err := hex.DecodeString(matcher.SHA256); err != nil || len(digest) != sha256.Size {
+				el = append(el, field.Invalid(path.Child("sha256"), matcher.SHA256, "filter sha256 must be a hex-encoded SHA-256 fingerprint"))
+			}
+		}
+
+		if len(matcher.Subject) > 0 {
+			count++
+		}
+
+		if len(matcher.SubjectRegex) > 0 {
+			count++
+
+			if _, err := regexp.Compile(matcher.SubjectRegex); err != nil {
+				el = append(el, field.Invalid(path.Child("subjectRegex"), matcher.SubjectRegex, err.Error()))
+			}
+		}
+
+		if count != 1 {
+			el = append(el, field.Forbidden(path, fmt.Sprintf("must define exactly one of sha256, subject or subjectRegex but found %d", count)))
+		}
+	}
+
+	return el
+}
+
+// parseInLineCertificates validates the data of an inline source in the same
+// way as the controller, and returns its certificates.
+func parseInLineCertificates(data string) ([]*x509.Certificate, error) {
+	sanitized, err := util.ValidateAndSanitizePEMBundle([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("inline source must be a valid PEM bundle: %w", err)
+	}
+
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(sanitized); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("inline source must be a valid PEM bundle: %w", err)
+		}
+
+		certificates = append(certificates, cert)
+	}
+
+	return certificates, nil
+}
+
+// validateCARequirement validates that all certificates of an inline source
+// meet the CA requirement of the Bundle.
+func validateCARequirement(path *field.Path, certificates []*x509.Certificate, requirement trustapi.CARequirement) field.ErrorList {
+	if len(requirement) == 0 || requirement == trustapi.CARequirementAny {
+		return nil
+	}
+
+	var el field.ErrorList
+	for _, cert := range certificates {
+		if err := util.ValidateCACertificate(cert, requirement == trustapi.CARequirementSelfSignedCA); err != nil {
+			el = append(el, field.Forbidden(path, err.Error()))
+		}
+	}
+
+	return el
+}
+
+// expiryWarnings returns a warning for each of the given certificates which
+// has expired or expires within the expiry warning window.
+func (v *validator) expiryWarnings(path *field.Path, certificates []*x509.Certificate) admission.Warnings {
+	if v.expiryWarningWindow <= 0 {
+		return nil
+	}
+
+	now := v.clock.Now()
+
+	var warnings admission.Warnings
+	for _, cert := range certificates {
+		switch {
+		case now.After(cert.NotAfter):
+			warnings = append(warnings, fmt.Sprintf("%s: certificate %q expired at %s", path, cert.Subject.String(), cert.NotAfter.UTC().Format(time.RFC3339)))
+		case now.Add(v.expiryWarningWindow).After(cert.NotAfter):
+			warnings = append(warnings, fmt.Sprintf("%s: certificate %q expires at %s", path, cert.Subject.String(), cert.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	return warnings
+}
+
+// validateCryptoPolicy validates the crypto policy of a Bundle.
+func validateCryptoPolicy(path *field.Path, policy *trustapi.CryptoPolicy) field.ErrorList {
+	var el field.ErrorList
+
+	if policy.MinRSAKeySize != nil && *policy.MinRSAKeySize <= 0 {
+		el = append(el, field.Invalid(path.Child("minRSAKeySize"), *policy.MinRSAKeySize, "minimum RSA key size must be positive"))
+	}
+
+	for i, curve := range policy.AllowedCurves {
+		if !cryptoPolicyCurves[curve] {
+			el = append(el, field.NotSupported(path.Child("allowedCurves").Index(i), curve, sortedKeys(cryptoPolicyCurves)))
+		}
+	}
+
+	// Signature algorithms which crypto/x509 doesn't have a name for are
+	// printed as their number, and can't be matched.
+	signatureAlgorithms := make(map[string]bool)
+	for algorithm := x509.MD2WithRSA; algorithm <= x509.PureEd25519; algorithm++ {
+		if name := algorithm.String(); name != strconv.Itoa(int(algorithm)) {
+			signatureAlgorithms[name] = true
+		}
+	}
+
+	for i, algorithm := range policy.ForbiddenSignatureAlgorithms {
+		if !signatureAlgorithms[algorithm] {
+			el = append(el, field.NotSupported(path.Child("forbiddenSignatureAlgorithms").Index(i), algorithm, sortedKeys(signatureAlgorithms)))
+		}
+	}
+
+	return el
+}
+
+// cryptoPolicyCurves is the set of curves which can be allowed by a crypto
+// policy.
+var cryptoPolicyCurves = map[string]bool{
+	"P-224":   true,
+	"P-256":   true,
+	"P-384":   true,
+	"P-521":   true,
+	"Ed25519": true,
+}
+
+// sortedKeys returns the keys of the given set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// targetFormats are the formats which target keys can be encoded in.
+var targetFormats = map[string]bool{
+	string(trustapi.TargetFormatPEM):    true,
+	string(trustapi.TargetFormatJKS):    true,
+	string(trustapi.TargetFormatPKCS12): true,
+	string(trustapi.TargetFormatDER):    true,
+	string(trustapi.TargetFormatPKCS7):  true,
+}
+
+// validateTargetKeys validates the additional keys of a Bundle target. Every
+// key must be unique within the target, and passwords may only be set for
+// trust store formats.
+func validateTargetKeys(path *field.Path, target *trustapi.BundleTarget) field.ErrorList {
+	var el field.ErrorList
+
+	used := make(map[string]bool)
+	if target.ConfigMap != nil {
+		used[target.ConfigMap.Key] = true
+	}
+	if target.Secret != nil {
+		used[target.Secret.Key] = true
+	}
+	if formats := target.AdditionalFormats; formats != nil {
+		if formats.JKS != nil {
+			used[formats.JKS.Key] = true
+		}
+		if formats.PKCS12 != nil {
+			used[formats.PKCS12.Key] = true
+		}
+	}
+
+	for i, key := range target.Keys {
+		path := path.Index(i)
+
+		if len(key.Key) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), key.Key, "target key must be defined"))
+		} else if used[key.Key] {
+			el = append(el, field.Duplicate(path.Child("key"), key.Key))
+		}
+		used[key.Key] = true
+
+		if len(key.Format) > 0 && !targetFormats[string(key.Format)] {
+			el = append(el, field.NotSupported(path.Child("format"), key.Format, sortedKeys(targetFormats)))
+		}
+
+		if key.Format == trustapi.TargetFormatJKS || key.Format == trustapi.TargetFormatPKCS12 {
+			el = append(el, validatePassword(path, key.Password, key.PasswordSecretRef)...)
+		} else if key.Password != nil || key.PasswordSecretRef != nil {
+			el = append(el, field.Forbidden(path, "password may only be set for JKS and PKCS12 formats"))
+		}
+	}
+
+	return el
+}
+
+// validatePassword validates the password of a binary trust store, which can
+// be given either inline or as a reference to a Secret key.
+func validatePassword(path *field.Path, password *string, secretRef *trustapi.SecretKeySelector) field.ErrorList {
+	var el field.ErrorList
+
+	if secretRef == nil {
+		return el
+	}
+
+	if password != nil {
+		el = append(el, field.Forbidden(path, "must define at most one of password and passwordSecretRef"))
+	}
+
+	if len(secretRef.Name) == 0 {
+		el = append(el, field.Invalid(path.Child("passwordSecretRef", "name"), secretRef.Name, "password secret name must be defined"))
+	}
+
+	if len(secretRef.Key) == 0 {
+		el = append(el, field.Invalid(path.Child("passwordSecretRef", "key"), secretRef.Key, "password secret key must be defined"))
+	}
+
+	return el
+}
+
+// validateURLSource validates a URL source.
+func validateURLSource(path *field.Path, source *trustapi.URLSource) field.ErrorList {
+	var el field.ErrorList
+
+	if address, err := url.Parse(source.Address); err != nil {
+		el = append(el, field.Invalid(path.Child("address"), source.Address, err.Error()))
+	} else if address.Scheme != "https" || len(address.Host) == 0 {
+		el = append(el, field.Invalid(path.Child("address"), source.Address, "source url address must be an absolute https URL"))
+	}
+
+	if source.RefreshInterval != nil && source.RefreshInterval.Duration < minURLRefreshInterval {
+		el = append(el, field.Invalid(path.Child("refreshInterval"), source.RefreshInterval.Duration.String(),
+			fmt.Sprintf("source url refreshInterval must be at least %s", minURLRefreshInterval)))
+	}
+
+	if len(source.SHA256) > 0 {
+		if digest, err := hex.DecodeString(source.SHA256); err != nil || len(digest) != sha256.Size {
+			el = append(el, field.Invalid(path.Child("sha256"), source.SHA256, "source url sha256 must be a hex-encoded SHA-256 digest"))
+		}
+	}
+
+	return el
+}
+
+// validateSourceSelector validates a label selector used to select source
+// objects in the trust Namespace.
+func validateSourceSelector(path *field.Path, selector *metav1.LabelSelector) field.ErrorList {
+	var el field.ErrorList
+
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		el = append(el, field.Invalid(path, selector, "source selector must not be empty"))
+		return el
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		el = append(el, field.Invalid(path, selector, err.Error()))
+	}
+
+	return el
+}
+
+// configMapTargetName returns the name of the target ConfigMaps of the given
+// Bundle, which defaults to the name of the Bundle.
+func configMapTargetName(bundle *trustapi.Bundle) string {
+	if target := bundle.Spec.Target.ConfigMap; target != nil && len(target.Name) > 0 {
+		return target.Name
+	}
+	return bundle.Name
+}
+
+// validateConfigMapTargetName returns an error if the target ConfigMap name of
+// the given Bundle is already used by another Bundle, since both Bundles would
+// then fight over the same ConfigMaps.
+func (v *validator) validateConfigMapTargetName(ctx context.Context, bundle *trustapi.Bundle, path *field.Path) (field.ErrorList, error) {
+	var bundleList trustapi.BundleList
+	if err := v.lister.List(ctx, &bundleList); err != nil {
+		return nil, fmt.Errorf("failed to list Bundles: %w", err)
+	}
+
+	name := configMapTargetName(bundle)
+
+	var el field.ErrorList
+	for i := range bundleList.Items {
+		other := &bundleList.Items[i]
+		if other.Name == bundle.Name || other.Spec.Target.ConfigMap == nil {
+			continue
+		}
+
+		if configMapTargetName(other) == name {
+			el = append(el, field.Forbidden(path, fmt.Sprintf("target configMap %q is already the target of Bundle %q", name, other.Name)))
+		}
+	}
+
+	return el, nil
+}
+
+// validateBundleSourceCycles returns an error for every Bundle source of the
+// given Bundle which (transitively) references the given Bundle again.
+// Referenced Bundles which don't exist are ignored, since they can't be part
+// of a cycle yet.
+func (v *validator) validateBundleSourceCycles(ctx context.Context, bundle *trustapi.Bundle, path *field.Path) (field.ErrorList, error) {
+	var el field.ErrorList
+
+	for i, source := range bundle.Spec.Sources {
+		if source.Bundle == nil || len(source.Bundle.Name) == 0 {
+			continue
+		}
+
+		chain, err := v.findBundleSourceCycle(ctx, bundle.Name, source.Bundle.Name, []string{bundle.Name}, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+
+		if chain != nil {
+			el = append(el, field.Forbidden(path.Child("["+strconv.Itoa(i)+"]", "bundle", "name"),
+				fmt.Sprintf("bundle source cycle detected: %s", strings.Join(chain, " -> "))))
+		}
+	}
+
+	return el, nil
+}
+
+// findBundleSourceCycle walks the Bundle sources starting at the Bundle with
+// the given name. It returns the chain of Bundle names which leads back to the
+// root Bundle, or nil if there is no such chain.
+func (v *validator) findBundleSourceCycle(ctx context.Context, root, name string, chain []string, visited map[string]bool) ([]string, error) {
+	chain = append(chain[:len(chain):len(chain)], name)
+	if name == root {
+		return chain, nil
+	}
+
+	if visited[name] {
+		return nil, nil
+	}
+	visited[name] = true
+
+	var bundle trustapi.Bundle
+	err := v.lister.Get(ctx, client.ObjectKey{Name: name}, &bundle)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Bundle %s: %w", name, err)
+	}
+
+	for _, source := range bundle.Spec.Sources {
+		if source.Bundle == nil {
+			continue
+		}
+
+		cycle, err := v.findBundleSourceCycle(ctx, root, source.Bundle.Name, chain, visited)
+		if err != nil || cycle != nil {
+			return cycle, err
+		}
+	}
+
+	return nil, nil
+}
@@ -18,10 +18,14 @@ package webhook
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 )
@@ -29,12 +33,38 @@ import (
 // Options are options for running the wehook.
 type Options struct {
 	Log logr.Logger
+
+	// ExpiryWarningWindow is the window before expiry in which certificates
+	// of inline sources are returned as warnings. Disabled if zero.
+	ExpiryWarningWindow time.Duration
+
+	// AuthorizeSources, if true, requires the user creating or updating a
+	// Bundle to be allowed to read the Secrets and ConfigMaps it references
+	// in the trust Namespace, directly or through other Bundles, checked
+	// using SubjectAccessReviews. Referenced Secrets and ConfigMaps which
+	// don't exist are returned as warnings.
+	AuthorizeSources bool
+
+	// PodInjectionEnabled, if true, serves the webhook which injects the
+	// target ConfigMap of a Bundle into annotated Pods on PodInjectionPath.
+	PodInjectionEnabled bool
+
+	// TrustNamespace is the Namespace which Bundle sources are read from.
+	TrustNamespace string
 }
 
 // Register the webhook endpoints against the Manager.
 func Register(mgr manager.Manager, opts Options) error {
 	opts.Log.Info("registering webhook endpoints")
-	validator := &validator{log: opts.Log.WithName("validation")}
+	validator := &validator{
+		log:                 opts.Log.WithName("validation"),
+		lister:              mgr.GetAPIReader(),
+		expiryWarningWindow: opts.ExpiryWarningWindow,
+		clock:               clock.RealClock{},
+		authorizeSources:    opts.AuthorizeSources,
+		trustNamespace:      opts.TrustNamespace,
+		reviewClient:        mgr.GetClient(),
+	}
 	err := builder.WebhookManagedBy(mgr).
 		For(&trustapi.Bundle{}).
 		WithValidator(validator).
@@ -42,6 +72,14 @@ func Register(mgr manager.Manager, opts Options) error {
 	if err != nil {
 		return fmt.Errorf("error registering webhook: %v", err)
 	}
+
+	if opts.PodInjectionEnabled {
+		mgr.GetWebhookServer().Register(PodInjectionPath, &webhook.Admission{Handler: &podInjector{
+			log:     opts.Log.WithName("injection"),
+			lister:  mgr.GetAPIReader(),
+			decoder: admission.NewDecoder(mgr.GetScheme()),
+		}})
+	}
 	mgr.AddReadyzCheck("validator", mgr.GetWebhookServer().StartedChecker())
 	return nil
 }
@@ -289,6 +289,80 @@ Z6tGn6D/Qqc6f1zLXbBwHSs09dR2CQzreExZBfMzQsNhFRAbd03OIozUhfJFfbdT
 0E6yove+7u7Y/9waLd64NnHi/Hm3lCXRSHNboTXns5lndcEZOitHTtNCjv0xyBZm
 2tIMPNuzjsmhDYAPexZ3FL//2wmUspO8IFgV6dtxQ/PeEMMA3KgqlbbC1j+Qa3bb
 bP6MvPJwNQzcmRk13NfIRmPVNnGuV/u3gm3c
+-----END CERTIFICATE-----`
+
+	// NB: TestIntermediateCertificate is a CA certificate which is not self signed.
+	// Certificate:
+	//     Data:
+	//         Version: 3 (0x2)
+	//         Serial Number:
+	//             09:7a:37:04:a2:60:f6:ee:17:e2:89:bd:1f:a6:2d:63:15:6e:f1:6d
+	//         Signature Algorithm: ecdsa-with-SHA256
+	//         Issuer: O = cert-manager, CN = cmct-test-hierarchy-root
+	//         Validity
+	//             Not Before: Oct 16 11:08:57 2026 GMT
+	//             Not After : Oct 11 11:08:57 2046 GMT
+	//         Subject: O = cert-manager, CN = cmct-test-intermediate
+	//         X509v3 extensions:
+	//             X509v3 Basic Constraints: critical
+	//                 CA:TRUE, pathlen:0
+	//             X509v3 Key Usage: critical
+	//                 Certificate Sign, CRL Sign
+	//             X509v3 Subject Key Identifier:
+	//                 E6:F0:8F:DF:30:B5:E7:37:FD:8E:EA:3C:90:1A:C0:4A:55:06:2E:EF
+	//             X509v3 Authority Key Identifier:
+	//                 C6:50:58:0B:9F:AF:1D:61:4E:F0:37:B3:83:4E:4C:13:CF:0F:0E:78
+	TestIntermediateCertificate = `-----BEGIN CERTIFICATE-----
+MIIB2jCCAYCgAwIBAgIUCXo3BKJg9u4X4om9H6YtYxVu8W0wCgYIKoZIzj0EAwIw
+OjEVMBMGA1UECgwMY2VydC1tYW5hZ2VyMSEwHwYDVQQDDBhjbWN0LXRlc3QtaGll
+cmFyY2h5LXJvb3QwHhcNMjYxMDE2MTEwODU3WhcNNDYxMDExMTEwODU3WjA4MRUw
+EwYDVQQKDAxjZXJ0LW1hbmFnZXIxHzAdBgNVBAMMFmNtY3QtdGVzdC1pbnRlcm1l
+ZGlhdGUwWTATBgcqhkjOPQIBBggqhkjOPQMBBwNCAAQFBFAXCToHQdUfzMxKfOSt
+iswwwnbC1sNK96cXJndtZnQGHkYqDTS7lMVjCrHU7ilGkppuC/r/y+nMkiiFT7KB
+o2YwZDASBgNVHRMBAf8ECDAGAQH/AgEAMA4GA1UdDwEB/wQEAwIBBjAdBgNVHQ4E
+FgQU5vCP3zC15zf9juo8kBrASlUGLu8wHwYDVR0jBBgwFoAUxlBYC5+vHWFO8Dez
+g05ME88PDngwCgYIKoZIzj0EAwIDSAAwRQIhALppEfQFkwGXDsSWFARhxZ2SnJ9q
+wS6xZWTb/wQJCi43AiAoByXUz53dmL94YhWKNd8aLk0a8rwrqAWZclS4S2x6Zw==
+-----END CERTIFICATE-----`
+
+	// NB: TestLeafCertificate is not a CA certificate. It is issued by
+	// TestIntermediateCertificate.
+	// Certificate:
+	//     Data:
+	//         Version: 3 (0x2)
+	//         Serial Number:
+	//             6d:38:37:aa:a8:1b:b8:43:3c:4b:f3:46:bb:7d:2b:ff:51:ac:81:c8
+	//         Signature Algorithm: ecdsa-with-SHA256
+	//         Issuer: O = cert-manager, CN = cmct-test-intermediate
+	//         Validity
+	//             Not Before: Oct 16 11:08:57 2026 GMT
+	//             Not After : Oct 11 11:08:57 2046 GMT
+	//         Subject: O = cert-manager, CN = cmct-test-leaf
+	//         X509v3 extensions:
+	//             X509v3 Basic Constraints: critical
+	//                 CA:FALSE
+	//             X509v3 Key Usage: critical
+	//                 Digital Signature
+	//             X509v3 Extended Key Usage:
+	//                 TLS Web Server Authentication
+	//             X509v3 Subject Alternative Name:
+	//                 DNS:cmct-test-leaf.example.com
+	//             X509v3 Subject Key Identifier:
+	//                 FC:A3:B8:D3:2F:E6:DE:88:26:84:E9:94:56:C9:99:4A:45:12:4D:10
+	//             X509v3 Authority Key Identifier:
+	//                 E6:F0:8F:DF:30:B5:E7:37:FD:8E:EA:3C:90:1A:C0:4A:55:06:2E:EF
+	TestLeafCertificate = `-----BEGIN CERTIFICATE-----
+MIICCDCCAa6gAwIBAgIUbTg3qqgbuEM8S/NGu30r/1GsgcgwCgYIKoZIzj0EAwIw
+ODEVMBMGA1UECgwMY2VydC1tYW5hZ2VyMR8wHQYDVQQDDBZjbWN0LXRlc3QtaW50
+ZXJtZWRpYXRlMB4XDTI2MTAxNjExMDg1N1oXDTQ2MTAxMTExMDg1N1owMDEVMBMG
+A1UECgwMY2VydC1tYW5hZ2VyMRcwFQYDVQQDDA5jbWN0LXRlc3QtbGVhZjBZMBMG
+ByqGSM49AgEGCCqGSM49AwEHA0IABG/2jFwYkrfp+gp11lBvcGYk7MetkoNV75O0
+Ue2xu84VEmK6iHECg6vOkug+yGMoetppMa7Q4v0SDGEgyD7OkMqjgZ0wgZowDAYD
+VR0TAQH/BAIwADAOBgNVHQ8BAf8EBAMCB4AwEwYDVR0lBAwwCgYIKwYBBQUHAwEw
+JQYDVR0RBB4wHIIaY21jdC10ZXN0LWxlYWYuZXhhbXBsZS5jb20wHQYDVR0OBBYE
+FPyjuNMv5t6IJoTplFbJmUpFEk0QMB8GA1UdIwQYMBaAFObwj98wtec3/Y7qPJAa
+wEpVBi7vMAoGCCqGSM49BAMCA0gAMEUCICvNm+lPHKEgKagi1r9hz2IYbJPJB+NS
+TXSab9r5Ita3AiEAvCdXIDrxVA+4K46JH66jJEwclOlsPEuaUYJaQn3XGKY=
 -----END CERTIFICATE-----`
 )
 
@@ -76,6 +76,9 @@ func Test_DummyCertificateSanity(t *testing.T) {
 		"TestCertificate3": TestCertificate3,
 		"TestCertificate4": TestCertificate4,
 		"TestCertificate5": TestCertificate5,
+
+		"TestIntermediateCertificate": TestIntermediateCertificate,
+		"TestLeafCertificate":         TestLeafCertificate,
 	}
 
 	equalityMap := make(map[string]struct{})
@@ -128,7 +128,7 @@ func NewTestBundle(ctx context.Context, cl client.Client, opts bundle.Options, t
 				},
 			},
 			Target: trustapi.BundleTarget{
-				ConfigMap: &td.Target,
+				ConfigMap: &trustapi.ConfigMapTarget{KeySelector: td.Target},
 			},
 		},
 	}
@@ -141,15 +141,20 @@ func checkBundleSyncedInternal(ctx context.Context, cl client.Client, bundleName
 	var bundle trustapi.Bundle
 	Expect(cl.Get(ctx, client.ObjectKey{Name: bundleName}, &bundle)).NotTo(HaveOccurred())
 
+	configMapName := bundle.Name
+	if len(bundle.Spec.Target.ConfigMap.Name) > 0 {
+		configMapName = bundle.Spec.Target.ConfigMap.Name
+	}
+
 	var configMap corev1.ConfigMap
-	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: bundle.Name}, &configMap); err != nil {
-		return fmt.Errorf("failed to get configMap %s/%s when checking bundle sync: %w", namespace, bundle.Name, err)
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: configMapName}, &configMap); err != nil {
+		return fmt.Errorf("failed to get configMap %s/%s when checking bundle sync: %w", namespace, configMapName, err)
 	}
 
 	gotData := configMap.Data[bundle.Spec.Target.ConfigMap.Key]
 
 	if err := comparator(gotData); err != nil {
-		return fmt.Errorf("configMap %s/%s didn't have expected value: %w", namespace, bundle.Name, err)
+		return fmt.Errorf("configMap %s/%s didn't have expected value: %w", namespace, configMapName, err)
 	}
 
 	if bundle.Status.Target == nil {
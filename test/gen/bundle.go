@@ -61,12 +61,42 @@ func SetBundleStatus(status trustapi.BundleStatus) BundleModifier {
 	}
 }
 
+// SetBundleSources sets the Bundle object's spec sources as a BundleModifier.
+func SetBundleSources(sources []trustapi.BundleSource) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.Sources = sources
+	}
+}
+
+// SetBundleTarget sets the Bundle object's spec target as a BundleModifier.
+func SetBundleTarget(target trustapi.BundleTarget) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.Target = target
+	}
+}
+
 func SetBundleTargetAdditionalFormats(formats trustapi.AdditionalFormats) BundleModifier {
 	return func(bundle *trustapi.Bundle) {
 		bundle.Spec.Target.AdditionalFormats = &formats
 	}
 }
 
+// SetBundleCryptoPolicy sets the Bundle object's spec crypto policy as a
+// BundleModifier.
+func SetBundleCryptoPolicy(policy trustapi.CryptoPolicy) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.CryptoPolicy = &policy
+	}
+}
+
+// SetBundleCARequirement sets the Bundle object's spec CA requirement as a
+// BundleModifier.
+func SetBundleCARequirement(requirement trustapi.CARequirement) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.CARequirement = requirement
+	}
+}
+
 // SetResourceVersion sets the Bundle object's resource version as a
 // BundleModifier.
 func SetBundleResourceVersion(resourceVersion string) BundleModifier {
@@ -85,9 +115,25 @@ func SetBundleTargetNamespaceSelectorMatchLabels(matchLabels map[string]string)
 	}
 }
 
+// SetBundleTargetNamespaceSelector sets the Bundle object's spec target
+// namespace selector.
+func SetBundleTargetNamespaceSelector(nsSelector trustapi.NamespaceSelector) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.Target.NamespaceSelector = &nsSelector
+	}
+}
+
 // AppendBundleUsesDefaultPackage appends a source to the bundle which requests the default bundle package.
 func AppendBundleUsesDefaultPackage() BundleModifier {
 	return func(bundle *trustapi.Bundle) {
 		bundle.Spec.Sources = append(bundle.Spec.Sources, trustapi.BundleSource{UseDefaultCAs: pointer.Bool(true)})
 	}
 }
+
+// AppendBundleInLineSource appends an inline source with the given data to the
+// bundle.
+func AppendBundleInLineSource(data string) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.Sources = append(bundle.Spec.Sources, trustapi.BundleSource{InLine: pointer.String(data)})
+	}
+}
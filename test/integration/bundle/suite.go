@@ -293,7 +293,7 @@ var _ = Describe("Integration", func() {
 
 	It("should delete old targets and update to new ones when the Spec.Target is modified", func() {
 		testBundle.Spec.Target = trustapi.BundleTarget{
-			ConfigMap: &trustapi.KeySelector{Key: "changed-target-key"},
+			ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: "changed-target-key"}},
 		}
 
 		Expect(cl.Update(ctx, testBundle)).ToNot(HaveOccurred())
@@ -319,9 +319,9 @@ var _ = Describe("Integration", func() {
 
 	It("should delete old targets and update to new ones when a JKS file is requested in the target", func() {
 		testBundle.Spec.Target = trustapi.BundleTarget{
-			ConfigMap: &trustapi.KeySelector{Key: testData.Target.Key},
+			ConfigMap: &trustapi.ConfigMapTarget{KeySelector: trustapi.KeySelector{Key: testData.Target.Key}},
 			AdditionalFormats: &trustapi.AdditionalFormats{
-				JKS: &trustapi.KeySelector{Key: "myfile.jks"},
+				JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "myfile.jks"}},
 			},
 		}
 
@@ -396,6 +396,112 @@ var _ = Describe("Integration", func() {
 		}, eventuallyTimeout, eventuallyPollInterval).Should(BeTrue(), "checking that the data is written back to the target")
 	})
 
+	It("should keep keys and annotations of a target ConfigMap which are managed by others", func() {
+		var configMap corev1.ConfigMap
+		Expect(cl.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: testBundle.Name}, &configMap)).ToNot(HaveOccurred())
+
+		patch := client.MergeFrom(configMap.DeepCopy())
+		metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, "reloader.example.com/last-reloaded", "now")
+		configMap.Data["other-key"] = "other-data"
+		Expect(cl.Patch(ctx, &configMap, patch, client.FieldOwner("other-manager"))).ToNot(HaveOccurred())
+
+		newInLine := dummy.TestCertificate4
+		testBundle.Spec.Sources[2].InLine = &newInLine
+		Expect(cl.Update(ctx, testBundle)).ToNot(HaveOccurred())
+
+		expectedData := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate4)
+
+		Eventually(func() bool {
+			var configMap corev1.ConfigMap
+			Expect(cl.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: testBundle.Name}, &configMap)).ToNot(HaveOccurred())
+
+			return configMap.Annotations["reloader.example.com/last-reloaded"] == "now" &&
+				apiequality.Semantic.DeepEqual(configMap.Data, map[string]string{
+					testData.Target.Key: expectedData,
+					"other-key":         "other-data",
+				})
+		}, eventuallyTimeout, eventuallyPollInterval).Should(BeTrue(), "checking that the target was updated without removing the fields of other managers")
+	})
+
+	It("should take over a target ConfigMap written with Update before server-side apply was used, and remove its old keys", func() {
+		testNamespace := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "trust-bundle-integration-ns-",
+				Labels:       map[string]string{"pre-server-side-apply": "true"},
+			},
+		}
+		Expect(cl.Create(ctx, &testNamespace)).NotTo(HaveOccurred())
+
+		By("Creating a target ConfigMap the way trust-manager did before using server-side apply")
+		configMap := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pre-server-side-apply",
+				Namespace: testNamespace.Name,
+				Labels:    map[string]string{"old-label": "true"},
+			},
+			Data: map[string]string{
+				testData.Target.Key: "stale data",
+				"old-key":           "stale data",
+			},
+		}
+		Expect(cl.Create(ctx, &configMap, client.FieldOwner(bundle.FieldManager))).ToNot(HaveOccurred())
+
+		patch := client.MergeFrom(configMap.DeepCopy())
+		configMap.Data["other-key"] = "other-data"
+		Expect(cl.Patch(ctx, &configMap, patch, client.FieldOwner("other-manager"))).ToNot(HaveOccurred())
+
+		By("Creating a Bundle which targets the ConfigMap")
+		preSSABundle := &trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "pre-server-side-apply"},
+			Spec: trustapi.BundleSpec{
+				Sources: testBundle.Spec.Sources,
+				Target: trustapi.BundleTarget{
+					ConfigMap: &trustapi.ConfigMapTarget{KeySelector: testData.Target},
+					NamespaceSelector: &trustapi.NamespaceSelector{
+						MatchLabels: map[string]string{"pre-server-side-apply": "true"},
+					},
+				},
+			},
+		}
+		Expect(cl.Create(ctx, preSSABundle)).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(cl.Delete(ctx, preSSABundle)).ToNot(HaveOccurred())
+		})
+
+		Eventually(func() bool {
+			var configMap corev1.ConfigMap
+			Expect(cl.Get(ctx, client.ObjectKey{Namespace: testNamespace.Name, Name: preSSABundle.Name}, &configMap)).ToNot(HaveOccurred())
+
+			return len(configMap.Labels) == 0 &&
+				apiequality.Semantic.DeepEqual(configMap.Data, map[string]string{
+					testData.Target.Key: dummy.DefaultJoinedCerts(),
+					"other-key":         "other-data",
+				})
+		}, eventuallyTimeout, eventuallyPollInterval).Should(BeTrue(), "checking that the keys and labels previously written by trust-manager were replaced")
+
+		Expect(cl.Get(ctx, client.ObjectKey{Namespace: testNamespace.Name, Name: preSSABundle.Name}, &configMap)).ToNot(HaveOccurred())
+		for _, managedFields := range configMap.ManagedFields {
+			if managedFields.Manager == bundle.FieldManager {
+				Expect(managedFields.Operation).To(Equal(metav1.ManagedFieldsOperationApply), "expected trust-manager to only own fields by server-side apply")
+			}
+		}
+
+		By("Changing the target key of the Bundle")
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(preSSABundle), preSSABundle)).ToNot(HaveOccurred())
+		preSSABundle.Spec.Target.ConfigMap.Key = "changed-target-key"
+		Expect(cl.Update(ctx, preSSABundle)).ToNot(HaveOccurred())
+
+		Eventually(func() bool {
+			var configMap corev1.ConfigMap
+			Expect(cl.Get(ctx, client.ObjectKey{Namespace: testNamespace.Name, Name: preSSABundle.Name}, &configMap)).ToNot(HaveOccurred())
+
+			return apiequality.Semantic.DeepEqual(configMap.Data, map[string]string{
+				"changed-target-key": dummy.DefaultJoinedCerts(),
+				"other-key":          "other-data",
+			})
+		}, eventuallyTimeout, eventuallyPollInterval).Should(BeTrue(), "checking that the old target key was removed by server-side apply")
+	})
+
 	It("should only write to Namespaces where the namespace selector matches", func() {
 		// Create a new namespace for this test; GenerateName will populate the name after creation
 		// We use GenerateName to create a new uniquely-named namespace that shouldn't clash with any of